@@ -8,6 +8,10 @@ import (
 	"personal-finance-backend/internal/config"
 	"personal-finance-backend/internal/db"
 	"personal-finance-backend/internal/handler"
+	"personal-finance-backend/internal/repository"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/internal/storage"
+	"personal-finance-backend/internal/worker"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,9 +34,49 @@ func main() {
 
 	log.Println("Successfully connected to the database!")
 
+	if cfg.AutoMigrate {
+		applied, err := db.MigrateUp(context.Background(), dbConn)
+		for _, m := range applied {
+			log.Println("Applied migration", m.Name)
+		}
+		if err != nil {
+			log.Fatal("Migration failed:", err)
+		}
+	}
+
+	// Background workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store, err := storage.New(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to set up attachment storage:", err)
+	}
+
+	if cfg.RecurringInterval > 0 {
+		recurringService := service.NewRecurringService(
+			repository.NewRecurringRepository(dbConn),
+			repository.NewCategoryRepository(dbConn),
+			repository.NewAccountRepository(dbConn),
+		)
+		go worker.NewRecurringWorker(recurringService, cfg.RecurringInterval).Run(ctx)
+		log.Println("Recurring worker running every", cfg.RecurringInterval)
+	}
+
+	if cfg.PurgeInterval > 0 {
+		purgeService := service.NewPurgeService(
+			repository.NewTransactionRepository(dbConn),
+			repository.NewCategoryRepository(dbConn),
+			store,
+			cfg.SoftDeleteRetention,
+		)
+		go worker.NewPurgeWorker(purgeService, cfg.PurgeInterval).Run(ctx)
+		log.Println("Purge worker running every", cfg.PurgeInterval)
+	}
+
 	r := gin.Default()
 
-	handler.RegisterRoutes(r, dbConn, cfg)
+	handler.RegisterRoutes(r, dbConn, cfg, store)
 
 	log.Println("Server running on :" + cfg.AppPort)
 	if err := r.Run(":" + cfg.AppPort); err != nil {
@@ -0,0 +1,90 @@
+// Package main implements the command that applies and reverts the database
+// migrations embedded in the binary.
+//
+// Usage:
+//
+//	migrate up          apply every pending migration
+//	migrate down [N]    revert the last N migrations (default 1)
+//	migrate status      list migrations and whether they are applied
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"personal-finance-backend/internal/config"
+	"personal-finance-backend/internal/db"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dbConn, err := db.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to the database:", err)
+	}
+	defer dbConn.Close()
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := db.MigrateUp(ctx, dbConn)
+		for _, m := range applied {
+			log.Println("Applied", m.Name)
+		}
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		if len(applied) == 0 {
+			log.Println("Database is up to date")
+		}
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				usage()
+			}
+		}
+		reverted, err := db.MigrateDown(ctx, dbConn, steps)
+		for _, m := range reverted {
+			log.Println("Reverted", m.Name)
+		}
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+
+	case "status":
+		statuses, err := db.Status(ctx, dbConn)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			appliedAt := "-"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-9s %-20s %s\n", s.State, appliedAt, s.Name)
+		}
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [N] | status")
+	os.Exit(2)
+}
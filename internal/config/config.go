@@ -1,29 +1,110 @@
 // Package config Description: This file contains the configuration loader for the application using Viper.
 package config
 
-import "github.com/spf13/viper"
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
 	AppPort string
 
 	DBUrl string
 
-	JWTSecret string
+	AutoMigrate bool // apply pending migrations when the API server starts
+
+	JWTSecret     string
+	JWTExpire     time.Duration // lifetime of access tokens (JWT_EXPIRE_MINUTES)
+	JWTRefreshTTL time.Duration // lifetime of refresh tokens (JWT_REFRESH_DAYS)
 
 	AdminAPIKey string // master key for managing API keys (from env)
+
+	APIKeyRotationGrace time.Duration // how long the old secret of a rotated API key keeps working
+
+	RateLimitPerMinute int    // requests per minute for API keys without their own limit, 0 for unlimited
+	RateLimitStore     string // "memory" (single machine) or "postgres" (shared by all machines)
+
+	RecurringInterval time.Duration // how often due recurring transactions are generated, 0 disables the worker
+
+	SoftDeleteRetention time.Duration // how long deleted transactions and categories can still be restored
+	PurgeInterval       time.Duration // how often expired deleted rows are purged, 0 disables the worker
+
+	BaseCurrency string // ISO 4217 code that convert_to=base converts amounts into
+
+	StorageDriver string // where attachment files are kept: "local" or "s3"
+	StorageDir    string // directory used by the local driver
+	S3Endpoint    string // host[:port] of the S3-compatible store used by the s3 driver
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3UseSSL      bool
+
+	AttachmentMaxSize int64    // largest accepted attachment, in bytes (ATTACHMENT_MAX_MB)
+	AttachmentTypes   []string // MIME types accepted as attachments, as sniffed from the content
 }
 
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("RECURRING_INTERVAL", "1h")
+	viper.SetDefault("BASE_CURRENCY", "IDR")
+	viper.SetDefault("AUTO_MIGRATE", false)
+	viper.SetDefault("JWT_EXPIRE_MINUTES", 15)
+	viper.SetDefault("JWT_REFRESH_DAYS", 7)
+	viper.SetDefault("API_KEY_ROTATION_GRACE", "24h")
+	viper.SetDefault("RATE_LIMIT_PER_MINUTE", 120)
+	viper.SetDefault("RATE_LIMIT_STORE", "memory")
+	viper.SetDefault("SOFT_DELETE_RETENTION", "720h")
+	viper.SetDefault("PURGE_INTERVAL", "24h")
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_DIR", "data/attachments")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_USE_SSL", true)
+	viper.SetDefault("ATTACHMENT_MAX_MB", 10)
+	viper.SetDefault("ATTACHMENT_TYPES", "image/jpeg,image/png,image/webp,application/pdf")
+
 	// .env file is optional — in production, env vars are injected directly
 	_ = viper.ReadInConfig()
 
 	return &Config{
-		AppPort:     viper.GetString("APP_PORT"),
-		DBUrl:       viper.GetString("DB_URL"),
-		JWTSecret:   viper.GetString("JWT_SECRET"),
-		AdminAPIKey: viper.GetString("ADMIN_API_KEY"),
+		AppPort:             viper.GetString("APP_PORT"),
+		DBUrl:               viper.GetString("DB_URL"),
+		AutoMigrate:         viper.GetBool("AUTO_MIGRATE"),
+		JWTSecret:           viper.GetString("JWT_SECRET"),
+		JWTExpire:           time.Duration(viper.GetInt("JWT_EXPIRE_MINUTES")) * time.Minute,
+		JWTRefreshTTL:       time.Duration(viper.GetInt("JWT_REFRESH_DAYS")) * 24 * time.Hour,
+		AdminAPIKey:         viper.GetString("ADMIN_API_KEY"),
+		APIKeyRotationGrace: viper.GetDuration("API_KEY_ROTATION_GRACE"),
+		RateLimitPerMinute:  viper.GetInt("RATE_LIMIT_PER_MINUTE"),
+		RateLimitStore:      strings.ToLower(viper.GetString("RATE_LIMIT_STORE")),
+		RecurringInterval:   viper.GetDuration("RECURRING_INTERVAL"),
+		SoftDeleteRetention: viper.GetDuration("SOFT_DELETE_RETENTION"),
+		PurgeInterval:       viper.GetDuration("PURGE_INTERVAL"),
+		BaseCurrency:        strings.ToUpper(viper.GetString("BASE_CURRENCY")),
+		StorageDriver:       strings.ToLower(viper.GetString("STORAGE_DRIVER")),
+		StorageDir:          viper.GetString("STORAGE_DIR"),
+		S3Endpoint:          viper.GetString("S3_ENDPOINT"),
+		S3Region:            viper.GetString("S3_REGION"),
+		S3Bucket:            viper.GetString("S3_BUCKET"),
+		S3AccessKey:         viper.GetString("S3_ACCESS_KEY"),
+		S3SecretKey:         viper.GetString("S3_SECRET_KEY"),
+		S3UseSSL:            viper.GetBool("S3_USE_SSL"),
+		AttachmentMaxSize:   viper.GetInt64("ATTACHMENT_MAX_MB") << 20,
+		AttachmentTypes:     splitList(viper.GetString("ATTACHMENT_TYPES")),
 	}, nil
 }
+
+// splitList splits a comma-separated setting, dropping blank entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, strings.ToLower(item))
+		}
+	}
+	return items
+}
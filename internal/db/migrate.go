@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migration files are named NNN_description.sql. Everything after the
+// "-- +migrate Down" line undoes the migration; the rest applies it.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+// migrationLockID is the pg_advisory_lock key held while migrating, so
+// machines starting at the same time apply each migration exactly once.
+const migrationLockID int64 = 0x6d6967726174 // "migrat"
+
+// Migration is one embedded SQL file.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of the whole file
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	State     string // "applied", "pending", "modified" (file changed since it was applied) or "missing" (applied but no file)
+	AppliedAt *time.Time
+}
+
+// ErrChecksumMismatch is returned by MigrateUp when an applied migration's
+// file has been edited since it ran.
+var ErrChecksumMismatch = errors.New("applied migration has been modified")
+
+// LoadMigrations parses the embedded migration files, ordered by version.
+func LoadMigrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles)
+}
+
+// loadMigrations parses the .sql files in the migrations directory of fsys.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	seen := map[int]string{}
+	for _, e := range entries {
+		name := e.Name()
+		prefix, _, ok := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %s: file name must start with a version number", name)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(fsys, path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+		up, down, _ := strings.Cut(string(content), downMarker)
+		sum := sha256.Sum256(content)
+
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     strings.TrimSuffix(name, ".sql"),
+			Up:       strings.TrimSpace(strings.Replace(up, upMarker, "", 1)),
+			Down:     strings.TrimSpace(down),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// withMigrationLock runs fn on a single connection holding the migration
+// advisory lock, after making sure schema_migrations exists.
+func withMigrationLock(ctx context.Context, pool *pgxpool.Pool, fn func(conn *pgx.Conn) error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+		    version BIGINT PRIMARY KEY,
+		    name TEXT NOT NULL,
+		    checksum VARCHAR(64) NOT NULL,
+		    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn.Conn())
+}
+
+func appliedMigrations(ctx context.Context, conn *pgx.Conn) (map[int]appliedMigration, map[int]string, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]appliedMigration{}
+	names := map[int]string{}
+	for rows.Next() {
+		var version int
+		var name string
+		var a appliedMigration
+		if err := rows.Scan(&version, &name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = a
+		names[version] = name
+	}
+	return applied, names, rows.Err()
+}
+
+// MigrateUp applies every pending migration in version order, each in its
+// own database transaction. It refuses to run if an applied migration has
+// been modified. Returns the migrations that were applied.
+func MigrateUp(ctx context.Context, pool *pgxpool.Pool) ([]Migration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = withMigrationLock(ctx, pool, func(conn *pgx.Conn) error {
+		applied, _, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if a, ok := applied[m.Version]; ok && a.checksum != m.Checksum {
+				return fmt.Errorf("%w: %s", ErrChecksumMismatch, m.Name)
+			}
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					m.Version, m.Name, m.Checksum,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrateDown reverts the latest steps applied migrations, newest first.
+// Returns the migrations that were reverted.
+func MigrateDown(ctx context.Context, pool *pgxpool.Pool, steps int) ([]Migration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]Migration{}
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	var done []Migration
+	err = withMigrationLock(ctx, pool, func(conn *pgx.Conn) error {
+		applied, names, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for i, v := range versions {
+			if i == steps {
+				break
+			}
+			m, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("migration %s: file not found", names[v])
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %s: no %q section", m.Name, downMarker)
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every known migration, embedded or applied, by version.
+func Status(ctx context.Context, pool *pgxpool.Pool) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	err = withMigrationLock(ctx, pool, func(conn *pgx.Conn) error {
+		applied, names, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			s := MigrationStatus{Version: m.Version, Name: m.Name, State: "pending"}
+			if a, ok := applied[m.Version]; ok {
+				s.State = "applied"
+				if a.checksum != m.Checksum {
+					s.State = "modified"
+				}
+				s.AppliedAt = &a.appliedAt
+				delete(applied, m.Version)
+			}
+			statuses = append(statuses, s)
+		}
+		for v, a := range applied {
+			statuses = append(statuses, MigrationStatus{Version: v, Name: names[v], State: "missing", AppliedAt: &a.appliedAt})
+		}
+		return nil
+	})
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, err
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+	checksum := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	const create = "-- +migrate Up\nCREATE TABLE a (id INT);\n\n-- +migrate Down\nDROP TABLE a;\n"
+	const alter = "-- +migrate Up\nALTER TABLE a ADD b INT;\n"
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []int
+		wantErr      string
+	}{
+		{
+			"ordered by version, not by file name",
+			fstest.MapFS{"migrations/10_alter.sql": file(alter), "migrations/9_create.sql": file(create)},
+			[]int{9, 10}, "",
+		},
+		{
+			"leading zeros",
+			fstest.MapFS{"migrations/002_alter.sql": file(alter), "migrations/001_create.sql": file(create)},
+			[]int{1, 2}, "",
+		},
+		{
+			"duplicate version",
+			fstest.MapFS{"migrations/001_create.sql": file(create), "migrations/1_alter.sql": file(alter)},
+			nil, "share version 1",
+		},
+		{
+			"missing version",
+			fstest.MapFS{"migrations/create.sql": file(create)},
+			nil, "must start with a version number",
+		},
+		{
+			"non-numeric version",
+			fstest.MapFS{"migrations/v1_create.sql": file(create)},
+			nil, "must start with a version number",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadMigrations() error = %v", err)
+			}
+			if len(got) != len(tt.wantVersions) {
+				t.Fatalf("loadMigrations() returned %d migrations, want %d", len(got), len(tt.wantVersions))
+			}
+			for i, m := range got {
+				if m.Version != tt.wantVersions[i] {
+					t.Errorf("migrations[%d].Version = %d, want %d", i, m.Version, tt.wantVersions[i])
+				}
+				data := tt.files["migrations/"+m.Name+".sql"]
+				if data == nil {
+					t.Fatalf("migrations[%d].Name = %s, no such file", i, m.Name)
+				}
+				if want := checksum(string(data.Data)); m.Checksum != want {
+					t.Errorf("%s checksum = %s, want %s", m.Name, m.Checksum, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsSections(t *testing.T) {
+	got, err := loadMigrations(fstest.MapFS{
+		"migrations/001_create.sql": {Data: []byte("-- +migrate Up\nCREATE TABLE a (id INT);\n\n-- +migrate Down\nDROP TABLE a;\n")},
+		"migrations/002_alter.sql":  {Data: []byte("-- +migrate Up\nALTER TABLE a ADD b INT;\n")},
+	})
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		wantUp   string
+		wantDown string
+	}{
+		{"001_create", "CREATE TABLE a (id INT);", "DROP TABLE a;"},
+		{"002_alter", "ALTER TABLE a ADD b INT;", ""},
+	}
+	for i, tt := range tests {
+		m := got[i]
+		if m.Name != tt.name {
+			t.Errorf("migrations[%d].Name = %s, want %s", i, m.Name, tt.name)
+		}
+		if m.Up != tt.wantUp {
+			t.Errorf("%s Up = %q, want %q", m.Name, m.Up, tt.wantUp)
+		}
+		if m.Down != tt.wantDown {
+			t.Errorf("%s Down = %q, want %q", m.Name, m.Down, tt.wantDown)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %s has version %d, want %d", m.Name, m.Version, i+1)
+		}
+		if m.Up == "" || strings.Contains(m.Up, "-- +migrate") {
+			t.Errorf("migration %s has a malformed Up section", m.Name)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %s has no Down section", m.Name)
+		}
+	}
+}
@@ -1,3 +1,4 @@
+-- +migrate Up
 CREATE TABLE IF NOT EXISTS api_keys (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name VARCHAR(100) NOT NULL,
@@ -7,4 +8,7 @@ CREATE TABLE IF NOT EXISTS api_keys (
     last_used_at TIMESTAMP WITH TIME ZONE
 );
 
-CREATE INDEX idx_api_keys_key ON api_keys (key) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_api_keys_key ON api_keys (key) WHERE is_active = true;
+
+-- +migrate Down
+DROP TABLE IF EXISTS api_keys;
@@ -1,3 +1,4 @@
+-- +migrate Up
 CREATE TABLE IF NOT EXISTS categories (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name VARCHAR(50) NOT NULL UNIQUE,
@@ -7,10 +8,19 @@ CREATE TABLE IF NOT EXISTS categories (
 );
 
 -- Create enum type for transaction types
-CREATE TYPE transaction_type AS ENUM ('income', 'expense', 'transfer');
+-- (CREATE TYPE has no IF NOT EXISTS, hence the DO blocks)
+DO $$ BEGIN
+    CREATE TYPE transaction_type AS ENUM ('income', 'expense', 'transfer');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
 
 -- Create enum type for transaction status
-CREATE TYPE transaction_status AS ENUM ('pending', 'completed', 'cancelled');
+DO $$ BEGIN
+    CREATE TYPE transaction_status AS ENUM ('pending', 'completed', 'cancelled');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
 
 CREATE TABLE IF NOT EXISTS transactions (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -25,10 +35,10 @@ CREATE TABLE IF NOT EXISTS transactions (
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
 );
 
-CREATE INDEX idx_transactions_type ON transactions (type);
-CREATE INDEX idx_transactions_category_id ON transactions (category_id);
-CREATE INDEX idx_transactions_status ON transactions (status);
-CREATE INDEX idx_transactions_date ON transactions (date);
+CREATE INDEX IF NOT EXISTS idx_transactions_type ON transactions (type);
+CREATE INDEX IF NOT EXISTS idx_transactions_category_id ON transactions (category_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions (status);
+CREATE INDEX IF NOT EXISTS idx_transactions_date ON transactions (date);
 
 -- Seed default categories
 INSERT INTO categories (name, type) VALUES
@@ -45,3 +55,9 @@ INSERT INTO categories (name, type) VALUES
     ('Pendidikan', 'expense'),
     ('Lainnya (Pengeluaran)', 'expense')
 ON CONFLICT (name) DO NOTHING;
+
+-- +migrate Down
+DROP TABLE IF EXISTS transactions;
+DROP TYPE IF EXISTS transaction_status;
+DROP TYPE IF EXISTS transaction_type;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('bank', 'cash', 'ewallet', 'credit_card', 'other')),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    opening_balance NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- Link transactions to the account the money moved in or out of.
+-- Nullable so existing transactions stay valid.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id ON transactions (account_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_account_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,30 @@
+-- +migrate Up
+-- A transfer is stored as two linked transaction legs sharing a transfer_id:
+-- an 'out' leg debiting the source account and an 'in' leg crediting the
+-- destination account. Transfer legs carry no category.
+ALTER TABLE transactions ALTER COLUMN category_id DROP NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS transfer_id UUID;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS transfer_direction VARCHAR(3)
+    CHECK (transfer_direction IN ('out', 'in'));
+
+-- NOT VALID so legacy single-row 'transfer' transactions don't block the migration
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_transactions_transfer_leg;
+ALTER TABLE transactions ADD CONSTRAINT chk_transactions_transfer_leg CHECK (
+    (type = 'transfer' AND transfer_id IS NOT NULL AND transfer_direction IS NOT NULL AND account_id IS NOT NULL)
+    OR (type <> 'transfer' AND transfer_id IS NULL AND category_id IS NOT NULL)
+) NOT VALID;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_transfer_leg ON transactions (transfer_id, transfer_direction)
+    WHERE transfer_id IS NOT NULL;
+
+-- +migrate Down
+-- Transfer legs have no category, so they have to go before category_id
+-- can be required again.
+DELETE FROM transactions WHERE transfer_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_transactions_transfer_leg;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_transactions_transfer_leg;
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_direction;
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+ALTER TABLE transactions ALTER COLUMN category_id SET NOT NULL;
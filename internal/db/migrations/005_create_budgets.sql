@@ -0,0 +1,18 @@
+-- +migrate Up
+-- One monthly budget per expense category. The limit applies to every month
+-- from start_month onwards; with rollover enabled, whatever is left unspent
+-- at the end of a month is added to the next month's limit.
+CREATE TABLE IF NOT EXISTS budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id UUID NOT NULL UNIQUE REFERENCES categories(id) ON DELETE CASCADE,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    start_month DATE NOT NULL DEFAULT date_trunc('month', CURRENT_DATE)::date,
+    rollover BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CHECK (start_month = date_trunc('month', start_month)::date)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS budgets;
@@ -0,0 +1,41 @@
+-- +migrate Up
+-- Recurring rules generate regular transactions (salary, rent, subscriptions)
+-- when they come due. next_date is NULL once the schedule has finished.
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type transaction_type NOT NULL CHECK (type IN ('income', 'expense')),
+    category_id UUID NOT NULL REFERENCES categories(id),
+    account_id UUID REFERENCES accounts(id),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    description TEXT,
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    day_of_month SMALLINT CHECK (day_of_month BETWEEN 1 AND 31),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    max_occurrences INTEGER CHECK (max_occurrences > 0),
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    last_date DATE,
+    next_date DATE,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_transactions_next_date ON recurring_transactions (next_date)
+    WHERE is_active = true;
+
+-- Generated transactions point back at their rule. The unique index makes
+-- materialization idempotent: an occurrence can only ever be inserted once.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS recurring_id UUID
+    REFERENCES recurring_transactions(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS recurring_date DATE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_recurring_occurrence ON transactions (recurring_id, recurring_date)
+    WHERE recurring_id IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_recurring_occurrence;
+ALTER TABLE transactions DROP COLUMN IF EXISTS recurring_date;
+ALTER TABLE transactions DROP COLUMN IF EXISTS recurring_id;
+DROP TABLE IF EXISTS recurring_transactions;
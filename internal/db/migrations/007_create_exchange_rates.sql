@@ -0,0 +1,18 @@
+-- +migrate Up
+-- Exchange rates by effective date: 1 unit of from_currency = rate units of
+-- to_currency from `date` until the next rate for the pair. A rate is also
+-- used inverted (to -> from) when no direct rate exists.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    date DATE NOT NULL,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE (from_currency, to_currency, date),
+    CHECK (from_currency <> to_currency)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,56 @@
+-- +migrate Up
+-- Every row belongs to the principal that created it: a user (JWT) or an
+-- API key. owner_id holds that user's or key's ID; there is no foreign key
+-- because it may point at either table. Categories without an owner are the
+-- shared defaults every principal can read but nobody can change.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS owner_id UUID;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS owner_id UUID;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS owner_id UUID;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS owner_id UUID;
+ALTER TABLE recurring_transactions ADD COLUMN IF NOT EXISTS owner_id UUID;
+
+-- Rows created before ownership existed go to the oldest API key, which was
+-- the only kind of client. The seeded default categories stay shared.
+UPDATE categories SET owner_id = (SELECT id FROM api_keys ORDER BY created_at LIMIT 1)
+WHERE owner_id IS NULL AND name NOT IN (
+    'Gaji', 'Freelance', 'Investasi', 'Lainnya (Pemasukan)',
+    'Makanan & Minuman', 'Transportasi', 'Belanja', 'Tagihan',
+    'Hiburan', 'Kesehatan', 'Pendidikan', 'Lainnya (Pengeluaran)'
+);
+UPDATE transactions SET owner_id = (SELECT id FROM api_keys ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+UPDATE accounts SET owner_id = (SELECT id FROM api_keys ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+UPDATE budgets SET owner_id = (SELECT id FROM api_keys ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+UPDATE recurring_transactions SET owner_id = (SELECT id FROM api_keys ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+
+-- Category names are unique per owner, and among the shared categories
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_owner_name ON categories (owner_id, name)
+    WHERE owner_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_shared_name ON categories (name)
+    WHERE owner_id IS NULL;
+
+-- Each owner can budget a shared category once
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_category_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budgets_owner_category ON budgets (owner_id, category_id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_owner_date ON transactions (owner_id, date);
+CREATE INDEX IF NOT EXISTS idx_accounts_owner_id ON accounts (owner_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_transactions_owner_id ON recurring_transactions (owner_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_recurring_transactions_owner_id;
+DROP INDEX IF EXISTS idx_accounts_owner_id;
+DROP INDEX IF EXISTS idx_transactions_owner_date;
+
+DROP INDEX IF EXISTS idx_budgets_owner_category;
+ALTER TABLE budgets ADD CONSTRAINT budgets_category_id_key UNIQUE (category_id);
+
+DROP INDEX IF EXISTS idx_categories_shared_name;
+DROP INDEX IF EXISTS idx_categories_owner_name;
+ALTER TABLE categories ADD CONSTRAINT categories_name_key UNIQUE (name);
+
+ALTER TABLE recurring_transactions DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE budgets DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE accounts DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Existing keys keep full access
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{*}';
+
+-- +migrate Down
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,36 @@
+-- +migrate Up
+-- API keys are stored as the hex SHA-256 of the key plus its first eight
+-- characters, so a database dump doesn't leak working credentials. The
+-- prefix only helps telling keys apart in the admin list.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS key_hash VARCHAR(64);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS key_prefix VARCHAR(8);
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'api_keys' AND column_name = 'key') THEN
+        UPDATE api_keys
+        SET key_hash = encode(sha256(convert_to(key, 'UTF8')), 'hex'),
+            key_prefix = left(key, 8)
+        WHERE key_hash IS NULL;
+    END IF;
+END $$;
+
+ALTER TABLE api_keys ALTER COLUMN key_hash SET NOT NULL;
+ALTER TABLE api_keys ALTER COLUMN key_prefix SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_api_keys_key;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
+
+-- +migrate Down
+-- The plaintext keys can't be recovered: every key gets a random secret
+-- nobody knows and has to be re-created.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS key VARCHAR(64);
+UPDATE api_keys SET key = replace(gen_random_uuid()::text || gen_random_uuid()::text, '-', '') WHERE key IS NULL;
+ALTER TABLE api_keys ALTER COLUMN key SET NOT NULL;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_key_key UNIQUE (key);
+CREATE INDEX IF NOT EXISTS idx_api_keys_key ON api_keys (key) WHERE is_active = true;
+
+DROP INDEX IF EXISTS idx_api_keys_key_hash;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS key_prefix;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS key_hash;
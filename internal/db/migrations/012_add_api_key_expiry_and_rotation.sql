@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Keys stop working at expires_at, when set. Rotating a key moves its hash
+-- to previous_key_hash, which keeps working until previous_expires_at so
+-- clients can switch over without downtime.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS previous_key_hash VARCHAR(64);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS previous_key_prefix VARCHAR(8);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS previous_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_previous_key_hash ON api_keys (previous_key_hash)
+    WHERE previous_key_hash IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_api_keys_previous_key_hash;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS previous_expires_at;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS previous_key_prefix;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS previous_key_hash;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS expires_at;
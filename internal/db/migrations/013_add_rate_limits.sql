@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Requests per minute allowed for a key; NULL uses RATE_LIMIT_PER_MINUTE
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rate_limit_per_minute INTEGER
+    CHECK (rate_limit_per_minute > 0);
+
+-- Token buckets shared by every machine when RATE_LIMIT_STORE=postgres.
+-- allowed records whether the last request took a token.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(100) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS rate_limit_buckets;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS rate_limit_per_minute;
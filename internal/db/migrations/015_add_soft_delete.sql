@@ -0,0 +1,35 @@
+-- +migrate Up
+-- Deleted transactions and categories are kept, hidden from every read,
+-- until the purge job removes them after the retention period.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- A deleted category's name can be used again
+DROP INDEX IF EXISTS idx_categories_owner_name;
+DROP INDEX IF EXISTS idx_categories_shared_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_owner_name_live ON categories (owner_id, name)
+    WHERE owner_id IS NOT NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_shared_name_live ON categories (name)
+    WHERE owner_id IS NULL AND deleted_at IS NULL;
+
+-- +migrate Down
+DELETE FROM transactions WHERE deleted_at IS NOT NULL;
+DELETE FROM categories c WHERE deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.category_id = c.id)
+    AND NOT EXISTS (SELECT 1 FROM recurring_transactions r WHERE r.category_id = c.id);
+UPDATE categories SET deleted_at = NULL WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_categories_shared_name_live;
+DROP INDEX IF EXISTS idx_categories_owner_name_live;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_owner_name ON categories (owner_id, name)
+    WHERE owner_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_shared_name ON categories (name)
+    WHERE owner_id IS NULL;
+
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+DROP INDEX IF EXISTS idx_transactions_deleted_at;
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Categories form a tree: "Food > Groceries", "Food > Restaurants". A child
+-- has the same type as its parent; the API prevents cycles. Purging a parent
+-- turns its remaining children into top-level categories.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id) WHERE parent_id IS NOT NULL;
+
+-- Budgets can count the spending of the category's subcategories too
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS include_subcategories BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE budgets DROP COLUMN IF EXISTS include_subcategories;
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
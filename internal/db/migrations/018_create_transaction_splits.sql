@@ -0,0 +1,19 @@
+-- +migrate Up
+-- A split transaction divides its amount over several categories, like one
+-- supermarket receipt covering groceries and medicine. The lines add up to
+-- the transaction's amount; category reports and budgets count the lines
+-- instead of the transaction's own category.
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    category_id UUID NOT NULL REFERENCES categories(id),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    note TEXT,
+    UNIQUE (transaction_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_category_id ON transaction_splits (category_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS transaction_splits;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Receipts and invoices attached to transactions. The files themselves live
+-- in the configured storage under storage_key; they are removed when the
+-- transaction is purged.
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    sha256 CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_transaction_id ON attachments (transaction_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS attachments;
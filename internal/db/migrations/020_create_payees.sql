@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Merchants and other counterparties, so that spending can be totalled per
+-- payee instead of being buried in descriptions. Names are unique per owner
+-- regardless of case.
+CREATE TABLE IF NOT EXISTS payees (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    default_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payees_owner_name ON payees (owner_id, lower(name));
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS payee_id UUID REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_payee_id ON transactions (payee_id);
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS payee_id;
+DROP TABLE IF EXISTS payees;
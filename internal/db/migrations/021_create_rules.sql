@@ -0,0 +1,30 @@
+-- +migrate Up
+-- Auto-categorization rules. Every condition that is set must hold for a
+-- rule to match; the actions fill in the category, payee, tags and status of
+-- matching transactions. Rules run in priority order, lowest first.
+CREATE TABLE IF NOT EXISTS rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+
+    description_contains TEXT,
+    description_regex TEXT,
+    amount_min NUMERIC(12, 2),
+    amount_max NUMERIC(12, 2),
+    type VARCHAR(10) CHECK (type IN ('income', 'expense')),
+
+    set_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    set_payee_id UUID REFERENCES payees(id) ON DELETE SET NULL,
+    add_tags TEXT[] NOT NULL DEFAULT '{}',
+    set_status transaction_status,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rules_owner_id ON rules (owner_id, priority);
+
+-- +migrate Down
+DROP TABLE IF EXISTS rules;
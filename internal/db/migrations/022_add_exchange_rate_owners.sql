@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Exchange rates belong to an owner like every other row since 009, so one
+-- principal's rates never change another's converted amounts. Existing
+-- rates go to the oldest API key, as in 009.
+ALTER TABLE exchange_rates ADD COLUMN IF NOT EXISTS owner_id UUID;
+UPDATE exchange_rates SET owner_id = (SELECT id FROM api_keys ORDER BY created_at LIMIT 1) WHERE owner_id IS NULL;
+
+-- One rate per owner, pair and date
+ALTER TABLE exchange_rates DROP CONSTRAINT IF EXISTS exchange_rates_from_currency_to_currency_date_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchange_rates_owner_pair_date
+    ON exchange_rates (owner_id, from_currency, to_currency, date);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_exchange_rates_owner_pair_date;
+ALTER TABLE exchange_rates ADD CONSTRAINT exchange_rates_from_currency_to_currency_date_key
+    UNIQUE (from_currency, to_currency, date);
+ALTER TABLE exchange_rates DROP COLUMN IF EXISTS owner_id;
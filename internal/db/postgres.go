@@ -1,17 +1,30 @@
-// Package db provides a function to create a new PostgreSQL database connection.
+// Package db provides the PostgreSQL connection pool and applies the embedded schema migrations.
 package db
 
 import (
 	"context"
 	"fmt"
 
+	pgxdecimal "github.com/jackc/pgx-shopspring-decimal"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"personal-finance-backend/internal/config"
 )
 
 func New(cfg *config.Config) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(context.Background(), cfg.DBUrl)
+	poolCfg, err := pgxpool.ParseConfig(cfg.DBUrl)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database url: %w", err)
+	}
+
+	// Read and write NUMERIC columns as exact decimals instead of float64
+	poolCfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		pgxdecimal.Register(conn.TypeMap())
+		return nil
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
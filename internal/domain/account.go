@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Account struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Type           string          `json:"type"` // "bank", "cash", "ewallet", "credit_card" or "other"
+	Currency       string          `json:"currency"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	Balance        decimal.Decimal `json:"balance"` // opening balance + completed income - expense +/- transfers
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type CreateAccountRequest struct {
+	Name           string          `json:"name" binding:"required,min=1,max=100"`
+	Type           string          `json:"type" binding:"required,oneof=bank cash ewallet credit_card other"`
+	Currency       string          `json:"currency" binding:"omitempty,iso4217"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+}
+
+type UpdateAccountRequest struct {
+	Name           *string          `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Type           *string          `json:"type,omitempty" binding:"omitempty,oneof=bank cash ewallet credit_card other"`
+	OpeningBalance *decimal.Decimal `json:"opening_balance,omitempty"`
+}
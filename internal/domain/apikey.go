@@ -1,22 +1,90 @@
 // Package domain contains the domain models for the application.
 package domain
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// ScopeAll grants every scope, including ones added later.
+const ScopeAll = "*"
+
+// ApiKeyScopes are the permissions an API key can be given. Each resource
+// has a read and a write scope; reports are read-only.
+var ApiKeyScopes = []string{
+	"categories:read", "categories:write",
+	"accounts:read", "accounts:write",
+	"transactions:read", "transactions:write",
+	"tags:read", "tags:write",
+	"payees:read", "payees:write",
+	"rules:read", "rules:write",
+	"budgets:read", "budgets:write",
+	"recurring:read", "recurring:write",
+	"exchange_rates:read", "exchange_rates:write",
+	"reports:read",
+}
+
+// IsApiKeyScope reports whether scope can be granted to an API key.
+func IsApiKeyScope(scope string) bool {
+	return scope == ScopeAll || slices.Contains(ApiKeyScopes, scope)
+}
 
 type ApiKey struct {
 	ID         string     `json:"id"`
 	Name       string     `json:"name"`
 	Key        string     `json:"key,omitempty"` // only shown on creation
+	Prefix     string     `json:"prefix"`        // first characters of the key, to tell keys apart
+	Scopes     []string   `json:"scopes"`
 	IsActive   bool       `json:"is_active"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RateLimit  *int       `json:"rate_limit_per_minute,omitempty"` // RATE_LIMIT_PER_MINUTE when unset
+	Status     string     `json:"status"`                          // active, inactive or expired
 	CreatedAt  time.Time  `json:"created_at"`
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	// PreviousKey is the secret replaced by the last rotation
+	PreviousKey *PreviousApiKey `json:"previous_key,omitempty"`
+}
+
+// PreviousApiKey is a rotated-out secret that keeps working until ExpiresAt.
+type PreviousApiKey struct {
+	Prefix    string    `json:"prefix"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Status    string    `json:"status"` // active (in its grace period), inactive or expired
+}
+
+// HasScope reports whether the key may use scope. A write scope implies the
+// read scope of the same resource.
+func (k *ApiKey) HasScope(scope string) bool {
+	if slices.Contains(k.Scopes, ScopeAll) || slices.Contains(k.Scopes, scope) {
+		return true
+	}
+	resource, ok := strings.CutSuffix(scope, ":read")
+	return ok && slices.Contains(k.Scopes, resource+":write")
 }
 
 type CreateApiKeyRequest struct {
 	Name string `json:"name" binding:"required,min=1,max=100"`
+	// Scopes defaults to ["*"], full access, when omitted
+	Scopes    []string   `json:"scopes,omitempty" binding:"omitnil,min=1,dive,apikey_scope"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" binding:"omitempty,gt"` // never expires when omitted
+	RateLimit *int       `json:"rate_limit_per_minute,omitempty" binding:"omitempty,min=1,max=100000"`
 }
 
 type UpdateApiKeyRequest struct {
-	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
-	IsActive *bool   `json:"is_active,omitempty"`
+	Name      *string    `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Scopes    *[]string  `json:"scopes,omitempty" binding:"omitnil,min=1,dive,apikey_scope"`
+	IsActive  *bool      `json:"is_active,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" binding:"omitempty,gt"`
+	RateLimit *int       `json:"rate_limit_per_minute,omitempty" binding:"omitempty,min=1,max=100000"`
+}
+
+// RotateApiKeyRequest is the optional body of POST /admin/v1/api-keys/:id/rotate.
+type RotateApiKeyRequest struct {
+	// GracePeriodMinutes is how long the old secret keeps working, the
+	// API_KEY_ROTATION_GRACE setting when omitted
+	GracePeriodMinutes *int `json:"grace_period_minutes,omitempty" binding:"omitempty,min=0,max=43200"`
+	// ExpiresAt replaces the key's expiry; it is kept when omitted
+	ExpiresAt *time.Time `json:"expires_at,omitempty" binding:"omitempty,gt"`
 }
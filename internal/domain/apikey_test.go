@@ -0,0 +1,50 @@
+package domain
+
+import "testing"
+
+func TestApiKeyHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{"all grants reads", []string{ScopeAll}, "categories:read", true},
+		{"all grants writes", []string{ScopeAll}, "transactions:write", true},
+		{"exact read", []string{"reports:read"}, "reports:read", true},
+		{"exact write", []string{"budgets:write"}, "budgets:write", true},
+		{"write implies read", []string{"accounts:write"}, "accounts:read", true},
+		{"read does not imply write", []string{"accounts:read"}, "accounts:write", false},
+		{"other resource", []string{"accounts:write"}, "categories:read", false},
+		{"prefix of another resource", []string{"tags:write"}, "tag:read", false},
+		{"no scopes", nil, "categories:read", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &ApiKey{Scopes: tt.scopes}
+			if got := k.HasScope(tt.scope); got != tt.want {
+				t.Errorf("HasScope(%q) with %v = %v, want %v", tt.scope, tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsApiKeyScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{ScopeAll, true},
+		{"categories:read", true},
+		{"exchange_rates:write", true},
+		{"reports:read", true},
+		{"reports:write", false}, // reports are read-only
+		{"categories", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsApiKeyScope(tt.scope); got != tt.want {
+			t.Errorf("IsApiKeyScope(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+}
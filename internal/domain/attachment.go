@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Attachment is a file, such as a receipt photo or a PDF invoice, attached to
+// a transaction.
+type Attachment struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	Filename      string    `json:"filename"`
+	ContentType   string    `json:"content_type"` // sniffed from the content, not taken from the upload
+	Size          int64     `json:"size"`         // in bytes
+	SHA256        string    `json:"sha256"`       // hex-encoded
+	StorageKey    string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Actor types recorded in the audit log.
+const (
+	ActorAPIKey = "api_key"
+	ActorUser   = "user"
+	ActorAdmin  = "admin"
+)
+
+// Actor is who made a request. The auth middlewares put it in the request
+// context so services can record it in the audit log.
+type Actor struct {
+	Type string
+	ID   string // API key or user ID, empty for the admin
+	Name string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor stored by WithActor, or the zero Actor.
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    *string         `json:"actor_id,omitempty"`
+	ActorName  string          `json:"actor_name"`
+	Action     string          `json:"action"` // create, import, update, delete, restore, merge or rotate
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=category transaction transfer api_key"`
+	EntityID   string `form:"entity_id"`
+	ActorType  string `form:"actor_type" binding:"omitempty,oneof=api_key user admin"`
+	ActorID    string `form:"actor_id"`
+	Action     string `form:"action" binding:"omitempty,oneof=create import update delete restore merge rotate"`
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00|datetime=2006-01-02"` // RFC 3339 or YYYY-MM-DD
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00|datetime=2006-01-02"`   // a date includes the whole day
+	Page       int    `form:"page"`
+	Limit      int    `form:"limit"`
+}
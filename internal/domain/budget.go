@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Budget struct {
+	ID                   string          `json:"id"`
+	CategoryID           string          `json:"category_id"`
+	CategoryName         string          `json:"category_name,omitempty"` // joined from categories
+	IncludeSubcategories bool            `json:"include_subcategories"`   // count spending in the category's descendants too
+	Amount               decimal.Decimal `json:"amount"`                  // monthly limit
+	Currency             string          `json:"currency"`
+	StartMonth           string          `json:"start_month"` // YYYY-MM
+	Rollover             bool            `json:"rollover"`    // carry unspent amounts into the next month
+	CreatedAt            time.Time       `json:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+}
+
+type CreateBudgetRequest struct {
+	CategoryID           string          `json:"category_id" binding:"required,uuid"`
+	IncludeSubcategories bool            `json:"include_subcategories"`
+	Amount               decimal.Decimal `json:"amount" binding:"required,gt=0"`
+	Currency             string          `json:"currency" binding:"omitempty,iso4217"`
+	StartMonth           string          `json:"start_month" binding:"omitempty,datetime=2006-01"` // defaults to the current month
+	Rollover             bool            `json:"rollover"`
+}
+
+type UpdateBudgetRequest struct {
+	IncludeSubcategories *bool            `json:"include_subcategories,omitempty"`
+	Amount               *decimal.Decimal `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	StartMonth           *string          `json:"start_month,omitempty" binding:"omitempty,datetime=2006-01"`
+	Rollover             *bool            `json:"rollover,omitempty"`
+}
+
+// BudgetStatus is a budget's position for a single month.
+type BudgetStatus struct {
+	BudgetID     string          `json:"budget_id"`
+	CategoryID   string          `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Currency     string          `json:"currency"`
+	Month        string          `json:"month"` // YYYY-MM
+	Limit        decimal.Decimal `json:"limit"`
+	RolledOver   decimal.Decimal `json:"rolled_over"` // unspent amount carried from previous months
+	Available    decimal.Decimal `json:"available"`   // limit + rolled_over
+	Spent        decimal.Decimal `json:"spent"`
+	Remaining    decimal.Decimal `json:"remaining"` // available - spent, negative when over budget
+}
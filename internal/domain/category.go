@@ -3,19 +3,54 @@ package domain
 import "time"
 
 type Category struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"` // "income" or "expense"
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`      // "income" or "expense", the same as the parent's
+	ParentID  *string    `json:"parent_id"` // nil for top-level categories
+	Shared    bool       `json:"shared"`    // built-in category, readable by everyone but not editable
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // only listed with include_deleted=true
+	Children  []Category `json:"children,omitempty"`   // only listed with tree=true
 }
 
 type CreateCategoryRequest struct {
-	Name string `json:"name" binding:"required,min=1,max=50"`
-	Type string `json:"type" binding:"required,oneof=income expense"`
+	Name     string  `json:"name" binding:"required,min=1,max=50"`
+	Type     string  `json:"type" binding:"required,oneof=income expense"`
+	ParentID *string `json:"parent_id,omitempty" binding:"omitempty,uuid"`
+}
+
+type CategoryFilter struct {
+	IncludeDeleted bool `form:"include_deleted"`
+	Tree           bool `form:"tree"` // nest subcategories under their parents
 }
 
 type UpdateCategoryRequest struct {
-	Name *string `json:"name,omitempty" binding:"omitempty,min=1,max=50"`
-	Type *string `json:"type,omitempty" binding:"omitempty,oneof=income expense"`
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=50"`
+	Type     *string `json:"type,omitempty" binding:"omitempty,oneof=income expense"`
+	ParentID *string `json:"parent_id,omitempty" binding:"omitempty,uuid|eq="` // "" makes it a top-level category
+}
+
+// DeleteCategoryRequest moves everything that uses the category to
+// ReassignTo before deleting it.
+type DeleteCategoryRequest struct {
+	ReassignTo string `form:"reassign_to" binding:"omitempty,uuid"`
+}
+
+// MergeCategoryRequest folds a category into TargetID, which must have the
+// same type.
+type MergeCategoryRequest struct {
+	TargetID string `json:"target_id" binding:"required,uuid"`
+}
+
+// CategoryUsage counts what refers to a category: its transactions (deleted
+// ones aside), recurring rules, subcategories, budgets, payees that default
+// to it and categorization rules that set it.
+type CategoryUsage struct {
+	Transactions          int `json:"transactions"`
+	RecurringTransactions int `json:"recurring_transactions"`
+	Subcategories         int `json:"subcategories"`
+	Budgets               int `json:"budgets"`
+	Payees                int `json:"payees"`
+	Rules                 int `json:"rules"`
 }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate converts from_currency into to_currency: 1 FromCurrency =
+// Rate ToCurrency, effective from Date until the pair's next rate.
+type ExchangeRate struct {
+	ID           string          `json:"id"`
+	Date         string          `json:"date"` // YYYY-MM-DD
+	FromCurrency string          `json:"from_currency"`
+	ToCurrency   string          `json:"to_currency"`
+	Rate         decimal.Decimal `json:"rate"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+type CreateExchangeRateRequest struct {
+	Date         string          `json:"date" binding:"required,datetime=2006-01-02"`
+	FromCurrency string          `json:"from_currency" binding:"required,iso4217"`
+	ToCurrency   string          `json:"to_currency" binding:"omitempty,iso4217,nefield=FromCurrency"` // defaults to the base currency
+	Rate         decimal.Decimal `json:"rate" binding:"required,gt=0"`
+}
+
+type BulkCreateExchangeRatesRequest struct {
+	Rates []CreateExchangeRateRequest `json:"rates" binding:"required,min=1,max=1000,dive"`
+}
+
+type UpdateExchangeRateRequest struct {
+	Date *string          `json:"date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	Rate *decimal.Decimal `json:"rate,omitempty" binding:"omitempty,gt=0"`
+}
+
+type ExchangeRateFilter struct {
+	FromCurrency string `form:"from_currency" binding:"omitempty,iso4217"`
+	ToCurrency   string `form:"to_currency" binding:"omitempty,iso4217"`
+	DateFrom     string `form:"date_from"` // YYYY-MM-DD
+	DateTo       string `form:"date_to"`   // YYYY-MM-DD
+	Page         int    `form:"page"`
+	Limit        int    `form:"limit"`
+}
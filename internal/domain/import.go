@@ -0,0 +1,43 @@
+package domain
+
+// ImportMapping describes how the columns of a bank statement CSV map onto
+// transactions. Columns are referenced by their header name; any preamble
+// lines before the header row are skipped.
+type ImportMapping struct {
+	DateColumn        string `json:"date_column" binding:"required"`
+	DateFormat        string `json:"date_format"` // Go layout, e.g. "02/01/2006"; defaults to "2006-01-02"
+	AmountColumn      string `json:"amount_column" binding:"required"`
+	DescriptionColumn string `json:"description_column"`
+
+	// SignConvention decides whether a row is income or expense:
+	//   "negative_expense" (default) - negative amounts are expenses
+	//   "positive_expense"           - positive amounts are expenses (credit card statements)
+	//   "indicator"                  - IndicatorColumn equals ExpenseIndicator for expenses (e.g. "DB"/"CR")
+	SignConvention   string `json:"sign_convention" binding:"omitempty,oneof=negative_expense positive_expense indicator"`
+	IndicatorColumn  string `json:"indicator_column" binding:"required_if=SignConvention indicator"`
+	ExpenseIndicator string `json:"expense_indicator" binding:"required_if=SignConvention indicator"`
+
+	// Number format; Indonesian "1.250.000,00" uses "," for decimals and "." for thousands
+	DecimalSeparator   string `json:"decimal_separator" binding:"omitempty,len=1"`   // defaults to "."
+	ThousandsSeparator string `json:"thousands_separator" binding:"omitempty,max=1"` // defaults to ","
+
+	DefaultIncomeCategoryID  *string `json:"default_income_category_id,omitempty" binding:"omitempty,uuid"`
+	DefaultExpenseCategoryID *string `json:"default_expense_category_id,omitempty" binding:"omitempty,uuid"`
+	AccountID                *string `json:"account_id,omitempty" binding:"omitempty,uuid"`
+	Currency                 string  `json:"currency" binding:"omitempty,iso4217"`
+	Status                   string  `json:"status" binding:"omitempty,oneof=pending completed cancelled"`
+}
+
+type ImportRowError struct {
+	Row   int    `json:"row"` // line number in the file
+	Error string `json:"error"`
+}
+
+type ImportResult struct {
+	DryRun       bool                       `json:"dry_run"`
+	TotalRows    int                        `json:"total_rows"`
+	ValidRows    int                        `json:"valid_rows"`
+	Imported     int                        `json:"imported"`
+	Errors       []ImportRowError           `json:"errors"`
+	Transactions []CreateTransactionRequest `json:"transactions,omitempty"` // parsed rows, dry run only
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	// Keep amounts as JSON numbers (e.g. 50000.5) rather than strings.
+	// The decimal is written verbatim, so no precision is lost on the wire.
+	decimal.MarshalJSONWithoutQuotes = true
+}
+
+// ErrAmountPrecision is returned when an amount has more decimal places than
+// its currency allows.
+var ErrAmountPrecision = errors.New("amount has more decimal places than the currency allows")
+
+// defaultCurrencyPrecision is the number of decimal places for currencies
+// not listed in currencyPrecision. Amounts are stored as NUMERIC(12, 2), so
+// two is also the maximum.
+const defaultCurrencyPrecision = 2
+
+// currencyPrecision lists currencies whose amounts have no fractional unit
+// in practice.
+var currencyPrecision = map[string]int32{
+	"IDR": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"CLP": 0,
+	"ISK": 0,
+	"PYG": 0,
+	"UGX": 0,
+	"XAF": 0,
+	"XOF": 0,
+}
+
+// CurrencyPrecision returns the number of decimal places allowed for amounts
+// in the given ISO 4217 currency.
+func CurrencyPrecision(currency string) int32 {
+	if p, ok := currencyPrecision[currency]; ok {
+		return p
+	}
+	return defaultCurrencyPrecision
+}
+
+// ValidateAmount checks that amount fits the precision of currency, e.g.
+// IDR 15000.50 is rejected while USD 15.50 is accepted.
+func ValidateAmount(amount decimal.Decimal, currency string) error {
+	precision := CurrencyPrecision(currency)
+	if !amount.Equal(amount.Truncate(precision)) {
+		return fmt.Errorf("%w (%s allows %d)", ErrAmountPrecision, currency, precision)
+	}
+	return nil
+}
+
+// FormatAmount renders amount with exactly the currency's decimal places.
+func FormatAmount(amount decimal.Decimal, currency string) string {
+	return amount.StringFixed(CurrencyPrecision(currency))
+}
+
+// RoundAmount rounds amount to the currency's decimal places, e.g. after
+// converting it with an exchange rate.
+func RoundAmount(amount decimal.Decimal, currency string) decimal.Decimal {
+	return amount.Round(CurrencyPrecision(currency))
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		wantErr  bool
+	}{
+		{"15000", "IDR", false},
+		{"15000.00", "IDR", false}, // trailing zeros are not extra precision
+		{"15000.50", "IDR", true},
+		{"1000", "JPY", false},
+		{"1000.1", "JPY", true},
+		{"15.50", "USD", false},
+		{"15.5", "USD", false},
+		{"15.505", "USD", true},
+		{"0.01", "EUR", false},
+		{"0.001", "XYZ", true}, // unlisted currencies allow two places
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.amount, func(t *testing.T) {
+			err := ValidateAmount(decimal.RequireFromString(tt.amount), tt.currency)
+			if tt.wantErr && !errors.Is(err, ErrAmountPrecision) {
+				t.Errorf("ValidateAmount() error = %v, want ErrAmountPrecision", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateAmount() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestFormatAndRoundAmount(t *testing.T) {
+	tests := []struct {
+		amount     string
+		currency   string
+		wantFormat string
+		wantRound  string
+	}{
+		{"15000", "IDR", "15000", "15000"},
+		{"15000.5", "IDR", "15001", "15001"},
+		{"15.5", "USD", "15.50", "15.5"},
+		{"15.555", "USD", "15.56", "15.56"},
+		{"-2.345", "EUR", "-2.35", "-2.35"},
+	}
+	for _, tt := range tests {
+		amount := decimal.RequireFromString(tt.amount)
+		if got := FormatAmount(amount, tt.currency); got != tt.wantFormat {
+			t.Errorf("FormatAmount(%s, %s) = %s, want %s", tt.amount, tt.currency, got, tt.wantFormat)
+		}
+		if got := RoundAmount(amount, tt.currency); !got.Equal(decimal.RequireFromString(tt.wantRound)) {
+			t.Errorf("RoundAmount(%s, %s) = %s, want %s", tt.amount, tt.currency, got, tt.wantRound)
+		}
+	}
+}
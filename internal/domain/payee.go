@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Payee is a merchant or other counterparty of transactions.
+type Payee struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	DefaultCategoryID   *string   `json:"default_category_id"`             // used for new transactions created without a category
+	DefaultCategoryName *string   `json:"default_category_name,omitempty"` // joined from categories
+	Transactions        int       `json:"transactions"`                    // number of transactions with the payee
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type CreatePayeeRequest struct {
+	Name              string  `json:"name" binding:"required,min=1,max=100"`
+	DefaultCategoryID *string `json:"default_category_id,omitempty" binding:"omitempty,uuid"`
+}
+
+type UpdatePayeeRequest struct {
+	Name              *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	DefaultCategoryID *string `json:"default_category_id,omitempty" binding:"omitempty,uuid|eq="` // "" removes the default
+}
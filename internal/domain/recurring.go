@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RecurringTransaction struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type"`
+	CategoryID     string          `json:"category_id"`
+	CategoryName   string          `json:"category_name,omitempty"` // joined from categories
+	AccountID      *string         `json:"account_id,omitempty"`
+	Amount         decimal.Decimal `json:"amount"`
+	Currency       string          `json:"currency"`
+	Description    *string         `json:"description,omitempty"`
+	Frequency      string          `json:"frequency"`              // "daily", "weekly", "monthly" or "yearly"
+	DayOfMonth     *int            `json:"day_of_month,omitempty"` // monthly/yearly only, clamped to the month's last day
+	StartDate      string          `json:"start_date"`             // YYYY-MM-DD
+	EndDate        *string         `json:"end_date,omitempty"`     // YYYY-MM-DD, inclusive
+	MaxOccurrences *int            `json:"max_occurrences,omitempty"`
+	Occurrences    int             `json:"occurrences"`         // transactions generated so far
+	LastDate       *string         `json:"last_date,omitempty"` // date of the last generated transaction
+	NextDate       *string         `json:"next_date,omitempty"` // nil once the schedule has finished
+	IsActive       bool            `json:"is_active"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type CreateRecurringTransactionRequest struct {
+	Type           string          `json:"type" binding:"required,oneof=income expense"`
+	CategoryID     string          `json:"category_id" binding:"required,uuid"`
+	AccountID      *string         `json:"account_id,omitempty" binding:"omitempty,uuid"`
+	Amount         decimal.Decimal `json:"amount" binding:"required,gt=0"`
+	Currency       string          `json:"currency" binding:"omitempty,iso4217"`
+	Description    *string         `json:"description,omitempty"`
+	Frequency      string          `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	DayOfMonth     *int            `json:"day_of_month,omitempty" binding:"omitempty,min=1,max=31"`
+	StartDate      string          `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate        *string         `json:"end_date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	MaxOccurrences *int            `json:"max_occurrences,omitempty" binding:"omitempty,gt=0"`
+}
+
+type UpdateRecurringTransactionRequest struct {
+	CategoryID     *string          `json:"category_id,omitempty" binding:"omitempty,uuid"`
+	AccountID      *string          `json:"account_id,omitempty" binding:"omitempty,uuid"`
+	Amount         *decimal.Decimal `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	Description    *string          `json:"description,omitempty"`
+	Frequency      *string          `json:"frequency,omitempty" binding:"omitempty,oneof=daily weekly monthly yearly"`
+	DayOfMonth     *int             `json:"day_of_month,omitempty" binding:"omitempty,min=1,max=31"`
+	EndDate        *string          `json:"end_date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	MaxOccurrences *int             `json:"max_occurrences,omitempty" binding:"omitempty,gt=0"`
+	IsActive       *bool            `json:"is_active,omitempty"`
+}
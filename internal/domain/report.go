@@ -0,0 +1,49 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// Reports are grouped by currency, since amounts in different currencies
+// can't be added together.
+
+type SummaryReport struct {
+	Currency string          `json:"currency"`
+	Income   decimal.Decimal `json:"income"`
+	Expense  decimal.Decimal `json:"expense"`
+	Net      decimal.Decimal `json:"net"` // income - expense
+	Count    int             `json:"count"`
+}
+
+type CategoryReport struct {
+	CategoryID   string          `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Type         string          `json:"type"`
+	Currency     string          `json:"currency"`
+	Total        decimal.Decimal `json:"total"`
+	Count        int             `json:"count"`
+}
+
+type TagReport struct {
+	TagID    string          `json:"tag_id"`
+	TagName  string          `json:"tag_name"`
+	Type     string          `json:"type"`
+	Currency string          `json:"currency"`
+	Total    decimal.Decimal `json:"total"`
+	Count    int             `json:"count"`
+}
+
+type PayeeReport struct {
+	PayeeID   string          `json:"payee_id"`
+	PayeeName string          `json:"payee_name"`
+	Type      string          `json:"type"`
+	Currency  string          `json:"currency"`
+	Total     decimal.Decimal `json:"total"`
+	Count     int             `json:"count"`
+}
+
+type MonthlyReport struct {
+	Month    string          `json:"month"` // YYYY-MM
+	Currency string          `json:"currency"`
+	Income   decimal.Decimal `json:"income"`
+	Expense  decimal.Decimal `json:"expense"`
+	Net      decimal.Decimal `json:"net"`
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rule fills in transactions whose description, amount and type match its
+// conditions. Rules run in priority order, lowest first; for the category,
+// payee and status the first matching rule that sets them wins, while tags
+// from every matching rule are added.
+type Rule struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Priority   int            `json:"priority"`
+	Enabled    bool           `json:"enabled"`
+	Conditions RuleConditions `json:"conditions"`
+	Actions    RuleActions    `json:"actions"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// RuleConditions must all hold for a rule to match; unset ones are ignored.
+type RuleConditions struct {
+	DescriptionContains *string          `json:"description_contains,omitempty" binding:"omitempty,min=1,max=200"` // case-insensitive
+	DescriptionRegex    *string          `json:"description_regex,omitempty" binding:"omitempty,min=1,max=200"`    // RE2 syntax, (?i) for case-insensitive
+	AmountMin           *decimal.Decimal `json:"amount_min,omitempty" binding:"omitempty,gt=0"`                    // inclusive
+	AmountMax           *decimal.Decimal `json:"amount_max,omitempty" binding:"omitempty,gt=0"`                    // inclusive
+	Type                *string          `json:"type,omitempty" binding:"omitempty,oneof=income expense"`          // set from the category when omitted
+}
+
+// RuleActions are applied to matching transactions.
+type RuleActions struct {
+	CategoryID *string  `json:"category_id,omitempty" binding:"omitempty,uuid"`
+	PayeeID    *string  `json:"payee_id,omitempty" binding:"omitempty,uuid"`
+	Tags       []string `json:"tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=50,excludesall=0x2C"`
+	Status     *string  `json:"status,omitempty" binding:"omitempty,oneof=pending completed cancelled"`
+}
+
+type CreateRuleRequest struct {
+	Name       string         `json:"name" binding:"required,min=1,max=100"`
+	Priority   int            `json:"priority"`
+	Enabled    *bool          `json:"enabled,omitempty"` // defaults to true
+	Conditions RuleConditions `json:"conditions"`
+	Actions    RuleActions    `json:"actions"`
+}
+
+// UpdateRuleRequest replaces the conditions or actions as a whole when
+// given.
+type UpdateRuleRequest struct {
+	Name       *string         `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Priority   *int            `json:"priority,omitempty"`
+	Enabled    *bool           `json:"enabled,omitempty"`
+	Conditions *RuleConditions `json:"conditions,omitempty"`
+	Actions    *RuleActions    `json:"actions,omitempty"`
+}
+
+// ApplyRulesRequest re-runs the enabled rules over the owner's transactions
+// dated within a range. Unlike new transactions, existing ones get the
+// rules' category, payee and status even when they already have one.
+type ApplyRulesRequest struct {
+	DateFrom string `json:"date_from" binding:"required,datetime=2006-01-02"`
+	DateTo   string `json:"date_to" binding:"required,datetime=2006-01-02"`
+	DryRun   bool   `json:"dry_run"` // preview the changes without saving them
+}
+
+// RuleChange is what applying the rules changes on one transaction. Fields
+// the rules leave alone are nil.
+type RuleChange struct {
+	TransactionID string   `json:"transaction_id"`
+	Date          string   `json:"date"`
+	Description   *string  `json:"description,omitempty"`
+	Rules         []string `json:"rules"` // names of the matching rules
+	CategoryID    *string  `json:"category_id,omitempty"`
+	PayeeID       *string  `json:"payee_id,omitempty"`
+	AddTags       []string `json:"add_tags,omitempty"`
+	Status        *string  `json:"status,omitempty"`
+}
+
+type ApplyRulesResult struct {
+	DryRun  bool         `json:"dry_run"`
+	Checked int          `json:"checked"` // transactions in the range
+	Changed int          `json:"changed"`
+	Changes []RuleChange `json:"changes"`
+}
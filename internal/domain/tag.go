@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+type Tag struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Transactions int       `json:"transactions"` // number of transactions carrying the tag
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Tag names can't contain commas, which separate them in the tags_any and
+// tags_all filters.
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50,excludesall=0x2C"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50,excludesall=0x2C"`
+}
+
+// NormalizeTag returns the stored form of a tag name: trimmed and lowercase.
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeTags normalizes tag names, dropping empty ones and duplicates,
+// and sorts them.
+func NormalizeTags(names []string) []string {
+	tags := []string{}
+	for _, name := range names {
+		if name = NormalizeTag(name); name != "" && !slices.Contains(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
@@ -1,47 +1,101 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type Transaction struct {
-	ID           string    `json:"id"`
-	Type         string    `json:"type"`
-	CategoryID   string    `json:"category_id"`
-	CategoryName string    `json:"category_name,omitempty"` // joined from categories
-	Amount       float64   `json:"amount"`
-	Currency     string    `json:"currency"`
-	Description  *string   `json:"description,omitempty"`
-	Status       string    `json:"status"`
-	Date         string    `json:"date"` // YYYY-MM-DD
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	CategoryID   *string         `json:"category_id,omitempty"`   // nil for transfer legs
+	CategoryName string          `json:"category_name,omitempty"` // joined from categories
+	AccountID    *string         `json:"account_id,omitempty"`
+	AccountName  *string         `json:"account_name,omitempty"` // joined from accounts
+	PayeeID      *string         `json:"payee_id,omitempty"`
+	PayeeName    *string         `json:"payee_name,omitempty"` // joined from payees
+	Amount       decimal.Decimal `json:"amount"`
+	Currency     string          `json:"currency"`
+	Description  *string         `json:"description,omitempty"`
+	Status       string          `json:"status"`
+	Date         string          `json:"date"` // YYYY-MM-DD
+	TransferID   *string         `json:"transfer_id,omitempty"`
+	Direction    *string         `json:"transfer_direction,omitempty"` // "out" or "in", transfer legs only
+	Tags         []string        `json:"tags"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	DeletedAt    *time.Time      `json:"deleted_at,omitempty"` // only listed with include_deleted=true
+
+	// Splits divide the amount over several categories, which category
+	// reports and budgets count instead of CategoryID.
+	Splits []TransactionSplit `json:"splits,omitempty"`
+
+	// Set when listing with convert_to. ConvertedAmount is nil when no
+	// exchange rate was effective on the transaction date.
+	ConvertedAmount   *decimal.Decimal `json:"converted_amount,omitempty"`
+	ConvertedCurrency string           `json:"converted_currency,omitempty"`
+}
+
+// TransactionSplit is one line of a split transaction.
+type TransactionSplit struct {
+	ID           string          `json:"id"`
+	CategoryID   string          `json:"category_id"`
+	CategoryName string          `json:"category_name"` // joined from categories
+	Amount       decimal.Decimal `json:"amount"`
+	Note         *string         `json:"note,omitempty"`
+}
+
+// SplitRequest is one line of a split transaction. The amounts of all lines
+// must add up to the transaction's amount.
+type SplitRequest struct {
+	CategoryID string          `json:"category_id" binding:"required,uuid"`
+	Amount     decimal.Decimal `json:"amount" binding:"required,gt=0"`
+	Note       *string         `json:"note,omitempty"`
 }
 
 type CreateTransactionRequest struct {
-	Type        string  `json:"type" binding:"required,oneof=income expense transfer"`
-	CategoryID  string  `json:"category_id" binding:"required,uuid"`
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Currency    string  `json:"currency" binding:"omitempty,len=3"`
-	Description *string `json:"description,omitempty"`
-	Status      string  `json:"status" binding:"omitempty,oneof=pending completed cancelled"`
-	Date        string  `json:"date" binding:"omitempty"` // YYYY-MM-DD
+	Type        string          `json:"type" binding:"required,oneof=income expense"` // transfers go through /transfers
+	CategoryID  string          `json:"category_id" binding:"omitempty,uuid"`         // defaults to the first split's, then from rules, then the payee's category
+	AccountID   *string         `json:"account_id,omitempty" binding:"omitempty,uuid"`
+	PayeeID     *string         `json:"payee_id,omitempty" binding:"omitempty,uuid"`
+	Amount      decimal.Decimal `json:"amount" binding:"required,gt=0"`
+	Currency    string          `json:"currency" binding:"omitempty,iso4217"`
+	Description *string         `json:"description,omitempty"`
+	Status      string          `json:"status" binding:"omitempty,oneof=pending completed cancelled"`
+	Date        string          `json:"date" binding:"omitempty"` // YYYY-MM-DD
+	Tags        []string        `json:"tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=50,excludesall=0x2C"`
+	Splits      []SplitRequest  `json:"splits,omitempty" binding:"omitempty,max=50,dive"`
 }
 
 type UpdateTransactionRequest struct {
-	Type        *string  `json:"type,omitempty" binding:"omitempty,oneof=income expense transfer"`
-	CategoryID  *string  `json:"category_id,omitempty" binding:"omitempty,uuid"`
-	Amount      *float64 `json:"amount,omitempty" binding:"omitempty,gt=0"`
-	Currency    *string  `json:"currency,omitempty" binding:"omitempty,len=3"`
-	Description *string  `json:"description,omitempty"`
-	Status      *string  `json:"status,omitempty" binding:"omitempty,oneof=pending completed cancelled"`
-	Date        *string  `json:"date,omitempty"`
+	Type        *string          `json:"type,omitempty" binding:"omitempty,oneof=income expense"`
+	CategoryID  *string          `json:"category_id,omitempty" binding:"omitempty,uuid"`
+	AccountID   *string          `json:"account_id,omitempty" binding:"omitempty,uuid"`
+	PayeeID     *string          `json:"payee_id,omitempty" binding:"omitempty,uuid|eq="` // "" removes the payee
+	Amount      *decimal.Decimal `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	Currency    *string          `json:"currency,omitempty" binding:"omitempty,iso4217"`
+	Description *string          `json:"description,omitempty"`
+	Status      *string          `json:"status,omitempty" binding:"omitempty,oneof=pending completed cancelled"`
+	Date        *string          `json:"date,omitempty"`
+	Tags        *[]string        `json:"tags,omitempty" binding:"omitnil,max=20,dive,min=1,max=50,excludesall=0x2C"` // replaces every tag, [] removes them
+	Splits      *[]SplitRequest  `json:"splits,omitempty" binding:"omitnil,max=50,dive"`                             // replaces every line, [] unsplits it
 }
 
 type TransactionFilter struct {
-	Type       string `form:"type"`
-	CategoryID string `form:"category_id"`
-	Status     string `form:"status"`
-	DateFrom   string `form:"date_from"` // YYYY-MM-DD
-	DateTo     string `form:"date_to"`   // YYYY-MM-DD
-	Page       int    `form:"page"`
-	Limit      int    `form:"limit"`
+	Type                 string   `form:"type"`
+	CategoryID           string   `form:"category_id"`
+	IncludeSubcategories bool     `form:"include_subcategories"` // category_id also matches its descendants
+	Tag                  string   `form:"tag"`
+	TagsAny              []string `form:"tags_any" collection_format:"csv"` // comma-separated, any of them
+	TagsAll              []string `form:"tags_all" collection_format:"csv"` // comma-separated, all of them
+	AccountID            string   `form:"account_id"`
+	PayeeID              string   `form:"payee_id"`
+	Status               string   `form:"status"`
+	DateFrom             string   `form:"date_from"`                                      // YYYY-MM-DD
+	DateTo               string   `form:"date_to"`                                        // YYYY-MM-DD
+	ConvertTo            string   `form:"convert_to" binding:"omitempty,iso4217|eq=base"` // "base" means the configured base currency
+	IncludeDeleted       bool     `form:"include_deleted"`                                // list soft-deleted transactions too; reports ignore it
+	Page                 int      `form:"page"`
+	Limit                int      `form:"limit"`
 }
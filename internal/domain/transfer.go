@@ -0,0 +1,38 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// Transfer moves money between two accounts. It is stored as a linked pair
+// of "transfer" transactions: From debits the source account and To credits
+// the destination account.
+type Transfer struct {
+	ID   string      `json:"id"`
+	From Transaction `json:"from"`
+	To   Transaction `json:"to"`
+}
+
+type CreateTransferRequest struct {
+	FromAccountID string          `json:"from_account_id" binding:"required,uuid"`
+	ToAccountID   string          `json:"to_account_id" binding:"required,uuid,nefield=FromAccountID"`
+	Amount        decimal.Decimal `json:"amount" binding:"required,gt=0"`
+	Description   *string         `json:"description,omitempty"`
+	Status        string          `json:"status" binding:"omitempty,oneof=pending completed cancelled"`
+	Date          string          `json:"date" binding:"omitempty"` // YYYY-MM-DD
+}
+
+type UpdateTransferRequest struct {
+	FromAccountID *string          `json:"from_account_id,omitempty" binding:"omitempty,uuid"`
+	ToAccountID   *string          `json:"to_account_id,omitempty" binding:"omitempty,uuid"`
+	Amount        *decimal.Decimal `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	Description   *string          `json:"description,omitempty"`
+	Status        *string          `json:"status,omitempty" binding:"omitempty,oneof=pending completed cancelled"`
+	Date          *string          `json:"date,omitempty"`
+}
+
+type TransferFilter struct {
+	AccountID string `form:"account_id"` // matches either side
+	DateFrom  string `form:"date_from"`  // YYYY-MM-DD
+	DateTo    string `form:"date_to"`    // YYYY-MM-DD
+	Page      int    `form:"page"`
+	Limit     int    `form:"limit"`
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type AccountHandler struct {
+	service *service.AccountService
+}
+
+func NewAccountHandler(s *service.AccountService) *AccountHandler {
+	return &AccountHandler{service: s}
+}
+
+func (h *AccountHandler) Create(c *gin.Context) {
+	var req domain.CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	account, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, domain.ErrAmountPrecision) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create account")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Account created", account)
+}
+
+func (h *AccountHandler) List(c *gin.Context) {
+	accounts, err := h.service.GetAll(c.Request.Context(), ownerID(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list accounts")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", accounts)
+}
+
+func (h *AccountHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	account, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Account not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", account)
+}
+
+func (h *AccountHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	account, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, domain.ErrAmountPrecision) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update account")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Account updated", account)
+}
+
+func (h *AccountHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Account not found")
+		return
+	}
+	if isForeignKeyViolation(err) {
+		response.Error(c, http.StatusConflict, "Account is still used by transactions or recurring transactions")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Account deleted", nil)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"personal-finance-backend/internal/domain"
@@ -8,6 +9,7 @@ import (
 	"personal-finance-backend/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 type ApiKeyHandler struct {
@@ -20,7 +22,8 @@ func NewApiKeyHandler(s *service.ApiKeyService) *ApiKeyHandler {
 
 // Create godoc
 // POST /api/v1/api-keys
-// Body: { "name": "my-frontend-app" }
+// Body: { "name": "my-frontend-app", "scopes": ["transactions:read", "reports:read"], "expires_at": "2027-01-01T00:00:00Z" }
+// scopes defaults to ["*"] (full access) when omitted.
 // Response: returns the full key (only time it's shown)
 func (h *ApiKeyHandler) Create(c *gin.Context) {
 	var req domain.CreateApiKeyRequest
@@ -66,7 +69,7 @@ func (h *ApiKeyHandler) GetByID(c *gin.Context) {
 
 // Update godoc
 // PATCH /api/v1/api-keys/:id
-// Body: { "name": "new-name", "is_active": false }
+// Body: { "name": "new-name", "scopes": ["transactions:write"], "is_active": false, "expires_at": "2027-06-01T00:00:00Z" }
 func (h *ApiKeyHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 
@@ -85,12 +88,46 @@ func (h *ApiKeyHandler) Update(c *gin.Context) {
 	response.Success(c, http.StatusOK, "API key updated", apiKey)
 }
 
+// Rotate godoc
+// POST /admin/v1/api-keys/:id/rotate
+// Body (optional): { "grace_period_minutes": 60, "expires_at": "2027-01-01T00:00:00Z" }
+// Response: returns the new key (only time it's shown). The old key keeps
+// working until previous_key.expires_at.
+func (h *ApiKeyHandler) Rotate(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.RotateApiKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	apiKey, err := h.service.Rotate(c.Request.Context(), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "API key not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to rotate API key")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "API key rotated. Save this key, it won't be shown again.", apiKey)
+}
+
 // Delete godoc
 // DELETE /api/v1/api-keys/:id
 func (h *ApiKeyHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+	err := h.service.Delete(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "API key not found")
+		return
+	}
+	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete API key")
 		return
 	}
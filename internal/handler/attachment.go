@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/internal/storage"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// multipartOverhead is allowed on top of the attachment size limit for the
+// multipart boundaries and headers around the file.
+const multipartOverhead = 1 << 20 // 1 MB
+
+type AttachmentHandler struct {
+	service *service.AttachmentService
+}
+
+func NewAttachmentHandler(s *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{service: s}
+}
+
+// Create godoc
+// POST /api/v1/transactions/:id/attachments
+// Multipart form:
+//   - file: the receipt or invoice
+//
+// The file type is sniffed from the content; the accepted types and the size
+// limit come from ATTACHMENT_TYPES and ATTACHMENT_MAX_MB.
+func (h *AttachmentHandler) Create(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxSize()+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.Error(c, http.StatusRequestEntityTooLarge, service.ErrAttachmentTooLarge.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Missing file")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Unable to read file")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Create(c.Request.Context(), ownerID(c), c.Param("id"), fileHeader.Filename, file, fileHeader.Size)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Transaction not found")
+		return
+	}
+	if errors.Is(err, service.ErrAttachmentTooLarge) {
+		response.Error(c, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrAttachmentType) {
+		response.Error(c, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrAttachmentEmpty) || errors.Is(err, service.ErrTransferLeg) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to save attachment")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Attachment saved", attachment)
+}
+
+func (h *AttachmentHandler) List(c *gin.Context) {
+	attachments, err := h.service.GetAll(c.Request.Context(), ownerID(c), c.Param("id"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Transaction not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list attachments")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", attachments)
+}
+
+// Download godoc
+// GET /api/v1/transactions/:id/attachments/:attachment_id
+// Returns the file itself, with its original filename.
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	attachment, content, err := h.service.Open(c.Request.Context(), ownerID(c), c.Param("id"), c.Param("attachment_id"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Attachment not found")
+		return
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Attachment %s has no file", c.Param("attachment_id"))
+		response.Error(c, http.StatusNotFound, "Attachment file is missing")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to read attachment")
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   strconv.Quote(attachment.SHA256),
+	})
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	err := h.service.Delete(c.Request.Context(), ownerID(c), c.Param("id"), c.Param("attachment_id"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Attachment not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete attachment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attachment deleted", nil)
+}
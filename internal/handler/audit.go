@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	service *service.AuditService
+}
+
+func NewAuditHandler(s *service.AuditService) *AuditHandler {
+	return &AuditHandler{service: s}
+}
+
+// List godoc
+// GET /admin/v1/audit
+// Query: entity_type, entity_id, actor_type, actor_id, action, from, to, page, limit
+// Newest entries first.
+func (h *AuditHandler) List(c *gin.Context) {
+	var filter domain.AuditFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, total, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list audit log")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", gin.H{
+		"entries": entries,
+		"total":   total,
+		"page":    filter.Page,
+		"limit":   filter.Limit,
+	})
+}
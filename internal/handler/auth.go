@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AuthHandler struct {
+	service *service.AuthService
+}
+
+func NewAuthHandler(s *service.AuthService) *AuthHandler {
+	return &AuthHandler{service: s}
+}
+
+// Register godoc
+// POST /api/v1/auth/register
+// Body: { "email": "budi@example.com", "password": "...", "name": "Budi" }
+// Response: the new user with an access and refresh token
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req domain.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.service.Register(c.Request.Context(), req)
+	if errors.Is(err, service.ErrEmailTaken) {
+		response.Error(c, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		response.Error(c, http.StatusBadRequest, "Password must be at most 72 bytes")
+		return
+	}
+	if errors.Is(err, service.ErrJWTNotConfigured) {
+		response.Error(c, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to register user")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "User registered", tokens)
+}
+
+// Login godoc
+// POST /api/v1/auth/login
+// Body: { "email": "budi@example.com", "password": "..." }
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req domain.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.service.Login(c.Request.Context(), req)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		response.Error(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrJWTNotConfigured) {
+		response.Error(c, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Logged in", tokens)
+}
+
+// Refresh godoc
+// POST /api/v1/auth/refresh
+// Body: { "refresh_token": "..." }
+// Response: a new access token and a new refresh token; the old one stops working
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req domain.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), req.RefreshToken)
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
+		response.Error(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrJWTNotConfigured) {
+		response.Error(c, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Token refreshed", tokens)
+}
+
+// Logout godoc
+// POST /api/v1/auth/logout
+// Body: { "refresh_token": "..." }
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req domain.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to log out")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Logged out", nil)
+}
+
+// Me godoc
+// GET /api/v1/auth/me
+// Requires: Authorization: Bearer <access_token>
+func (h *AuthHandler) Me(c *gin.Context) {
+	user, err := h.service.GetUser(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", user)
+}
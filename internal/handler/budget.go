@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type BudgetHandler struct {
+	service *service.BudgetService
+}
+
+func NewBudgetHandler(s *service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{service: s}
+}
+
+func (h *BudgetHandler) Create(c *gin.Context) {
+	var req domain.CreateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	budget, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrBudgetCategoryType) || errors.Is(err, domain.ErrAmountPrecision) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUnknownCategory) {
+		response.Error(c, http.StatusBadRequest, "Category not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create budget")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Budget created", budget)
+}
+
+func (h *BudgetHandler) List(c *gin.Context) {
+	budgets, err := h.service.GetAll(c.Request.Context(), ownerID(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list budgets")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", budgets)
+}
+
+func (h *BudgetHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	budget, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Budget not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", budget)
+}
+
+func (h *BudgetHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	budget, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, domain.ErrAmountPrecision) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update budget")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Budget updated", budget)
+}
+
+func (h *BudgetHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Budget not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete budget")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Budget deleted", nil)
+}
+
+// Status godoc
+// GET /api/v1/budgets/status?month=2026-02
+// Defaults to the current month.
+func (h *BudgetHandler) Status(c *gin.Context) {
+	month := time.Now().Format("2006-01")
+	if m := c.Query("month"); m != "" {
+		month = m
+	}
+
+	start, err := time.Parse("2006-01", month)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "month must be in YYYY-MM format")
+		return
+	}
+
+	statuses, err := h.service.Status(c.Request.Context(), ownerID(c), start)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to get budget status")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", statuses)
+}
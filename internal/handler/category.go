@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"personal-finance-backend/internal/domain"
@@ -8,6 +9,7 @@ import (
 	"personal-finance-backend/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 type CategoryHandler struct {
@@ -25,7 +27,15 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 		return
 	}
 
-	category, err := h.service.Create(c.Request.Context(), req)
+	category, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrParentType) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A category with this name already exists")
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create category")
 		return
@@ -34,8 +44,16 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 	response.Success(c, http.StatusCreated, "Category created", category)
 }
 
+// List godoc
+// GET /api/v1/categories?include_deleted=true&tree=true
 func (h *CategoryHandler) List(c *gin.Context) {
-	categories, err := h.service.GetAll(c.Request.Context())
+	var filter domain.CategoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	categories, err := h.service.GetAll(c.Request.Context(), ownerID(c), filter)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to list categories")
 		return
@@ -47,7 +65,7 @@ func (h *CategoryHandler) List(c *gin.Context) {
 func (h *CategoryHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	category, err := h.service.GetByID(c.Request.Context(), id)
+	category, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
 	if err != nil {
 		response.Error(c, http.StatusNotFound, "Category not found")
 		return
@@ -65,7 +83,24 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
-	category, err := h.service.Update(c.Request.Context(), id, req)
+	category, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Category not found")
+		return
+	}
+	if errors.Is(err, service.ErrSharedCategory) {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrParentType) ||
+		errors.Is(err, service.ErrCategoryCycle) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A category with this name already exists")
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update category")
 		return
@@ -74,13 +109,103 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Category updated", category)
 }
 
+// Delete godoc
+// DELETE /api/v1/categories/:id?reassign_to=<id>
+// A category still in use is only deleted with reassign_to, otherwise it
+// answers 409 with the number of transactions, recurring rules,
+// subcategories, budgets, payees and categorization rules using it.
 func (h *CategoryHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+	var req domain.DeleteCategoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	moved, err := h.service.Delete(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Category not found")
+		return
+	}
+	if errors.Is(err, service.ErrSharedCategory) {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrCategoryInUse) {
+		response.ErrorWithData(c, http.StatusConflict, err.Error(), moved)
+		return
+	}
+	if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrCategoryTypeMismatch) ||
+		errors.Is(err, service.ErrSameCategory) || errors.Is(err, service.ErrCategoryCycle) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete category")
 		return
 	}
 
+	if moved != nil {
+		response.Success(c, http.StatusOK, "Category deleted", moved)
+		return
+	}
 	response.Success(c, http.StatusOK, "Category deleted", nil)
 }
+
+// Merge godoc
+// POST /api/v1/categories/:id/merge
+// Folds the category into target_id and deletes it.
+func (h *CategoryHandler) Merge(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := h.service.Merge(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Category not found")
+		return
+	}
+	if errors.Is(err, service.ErrSharedCategory) {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrCategoryTypeMismatch) ||
+		errors.Is(err, service.ErrSameCategory) || errors.Is(err, service.ErrCategoryCycle) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to merge category")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Category merged", category)
+}
+
+// Restore godoc
+// POST /api/v1/categories/:id/restore
+// Brings back a deleted category.
+func (h *CategoryHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	category, err := h.service.Restore(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Deleted category not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A category with this name already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to restore category")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Category restored", category)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ExchangeRateHandler struct {
+	service *service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(s *service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: s}
+}
+
+func (h *ExchangeRateHandler) Create(c *gin.Context) {
+	var req domain.CreateExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rate, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrSameCurrency) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A rate for this currency pair and date already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create exchange rate")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Exchange rate created", rate)
+}
+
+// Bulk godoc
+// POST /api/v1/exchange-rates/bulk
+// Stores up to 1000 rates in one database transaction, replacing any rate
+// that already exists for the same pair and date.
+func (h *ExchangeRateHandler) Bulk(c *gin.Context) {
+	var req domain.BulkCreateExchangeRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	count, err := h.service.BulkCreate(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrSameCurrency) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to store exchange rates")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Exchange rates stored", gin.H{"count": count})
+}
+
+func (h *ExchangeRateHandler) List(c *gin.Context) {
+	var filter domain.ExchangeRateFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rates, total, err := h.service.GetAll(c.Request.Context(), ownerID(c), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list exchange rates")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", gin.H{
+		"exchange_rates": rates,
+		"total":          total,
+		"page":           filter.Page,
+		"limit":          filter.Limit,
+	})
+}
+
+func (h *ExchangeRateHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	rate, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Exchange rate not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", rate)
+}
+
+func (h *ExchangeRateHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rate, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Exchange rate not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A rate for this currency pair and date already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update exchange rate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Exchange rate updated", rate)
+}
+
+func (h *ExchangeRateHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Exchange rate not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete exchange rate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Exchange rate deleted", nil)
+}
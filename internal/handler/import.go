@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxImportSize caps the size of an uploaded statement.
+const maxImportSize = 10 << 20 // 10 MB
+
+type ImportHandler struct {
+	service *service.ImportService
+}
+
+func NewImportHandler(s *service.ImportService) *ImportHandler {
+	return &ImportHandler{service: s}
+}
+
+// Create godoc
+// POST /api/v1/imports?dry_run=true
+// Multipart form:
+//   - file:    the bank statement CSV
+//   - mapping: JSON column mapping, e.g.
+//     { "date_column": "Tanggal", "date_format": "02/01/2006", "amount_column": "Jumlah",
+//     "description_column": "Keterangan", "sign_convention": "indicator",
+//     "indicator_column": "DB/CR", "expense_indicator": "DB",
+//     "decimal_separator": ",", "default_expense_category_id": "..." }
+//
+// A dry run returns the parsed rows and per-row errors without saving
+// anything. A real import saves all rows in one database transaction, or
+// none if any row is invalid.
+func (h *ImportHandler) Create(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	dryRun := false
+	if v := c.DefaultQuery("dry_run", c.PostForm("dry_run")); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	var mapping domain.ImportMapping
+	if err := binding.JSON.BindBody([]byte(c.PostForm("mapping")), &mapping); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid mapping: "+err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Missing CSV file")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Unable to read CSV file")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Import(c.Request.Context(), ownerID(c), file, mapping, dryRun)
+	if errors.Is(err, service.ErrImportMapping) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrImportInvalidRows) {
+		response.ErrorWithData(c, http.StatusUnprocessableEntity, err.Error(), result)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to import transactions")
+		return
+	}
+
+	if dryRun {
+		response.Success(c, http.StatusOK, "Dry run, nothing was imported", result)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Transactions imported", result)
+}
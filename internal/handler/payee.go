@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type PayeeHandler struct {
+	service *service.PayeeService
+}
+
+func NewPayeeHandler(s *service.PayeeService) *PayeeHandler {
+	return &PayeeHandler{service: s}
+}
+
+func (h *PayeeHandler) Create(c *gin.Context) {
+	var req domain.CreatePayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payee, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrPayeeNameEmpty) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A payee with this name already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create payee")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Payee created", payee)
+}
+
+func (h *PayeeHandler) List(c *gin.Context) {
+	payees, err := h.service.GetAll(c.Request.Context(), ownerID(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list payees")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", payees)
+}
+
+func (h *PayeeHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	payee, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Payee not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", payee)
+}
+
+func (h *PayeeHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdatePayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payee, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Payee not found")
+		return
+	}
+	if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrPayeeNameEmpty) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A payee with this name already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update payee")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Payee updated", payee)
+}
+
+// Delete godoc
+// DELETE /api/v1/payees/:id
+// Its transactions are kept without a payee.
+func (h *PayeeHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Payee not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete payee")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Payee deleted", nil)
+}
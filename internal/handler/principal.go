@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ownerID returns the principal whose data the request works on: the signed
+// in user, or the API key when the request was made with one. It is set by
+// middleware.JWTAuth and middleware.APIKeyAuth.
+func ownerID(c *gin.Context) string {
+	return c.GetString("owner_id")
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint
+// violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key
+// violation, such as deleting a row that other rows still refer to.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type RecurringHandler struct {
+	service *service.RecurringService
+}
+
+func NewRecurringHandler(s *service.RecurringService) *RecurringHandler {
+	return &RecurringHandler{service: s}
+}
+
+// Create godoc
+// POST /api/v1/recurring
+// Body: { "type": "income", "category_id": "...", "amount": 10000000, "frequency": "monthly", "day_of_month": 25, "start_date": "2026-01-25" }
+func (h *RecurringHandler) Create(c *gin.Context) {
+	var req domain.CreateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrInvalidSchedule) || errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, domain.ErrAmountPrecision) ||
+		errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrUnknownAccount) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create recurring transaction")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Recurring transaction created", rule)
+}
+
+// List godoc
+// GET /api/v1/recurring
+func (h *RecurringHandler) List(c *gin.Context) {
+	rules, err := h.service.GetAll(c.Request.Context(), ownerID(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list recurring transactions")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", rules)
+}
+
+// GetByID godoc
+// GET /api/v1/recurring/:id
+func (h *RecurringHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	rule, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Recurring transaction not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", rule)
+}
+
+// Update godoc
+// PATCH /api/v1/recurring/:id
+// Body: { "amount": 12000000, "is_active": false }
+func (h *RecurringHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, service.ErrInvalidSchedule) || errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, domain.ErrAmountPrecision) ||
+		errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrUnknownAccount) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Recurring transaction or account not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update recurring transaction")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Recurring transaction updated", rule)
+}
+
+// Delete godoc
+// DELETE /api/v1/recurring/:id
+// Transactions already generated by the rule are kept.
+func (h *RecurringHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.Delete(c.Request.Context(), ownerID(c), id); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete recurring transaction")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Recurring transaction deleted", nil)
+}
+
+// Preview godoc
+// GET /api/v1/recurring/:id/preview?count=5
+// Returns the dates of the next occurrences (1-100, default 5).
+func (h *RecurringHandler) Preview(c *gin.Context) {
+	id := c.Param("id")
+
+	count := 5
+	if v := c.Query("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			response.Error(c, http.StatusBadRequest, "count must be between 1 and 100")
+			return
+		}
+		count = n
+	}
+
+	dates, err := h.service.Preview(c.Request.Context(), ownerID(c), id, count)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Recurring transaction not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", dates)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler serves aggregated views over transactions. All endpoints
+// take the same filters as GET /api/v1/transactions: type, category_id,
+// include_subcategories, tag, tags_any, tags_all, account_id, payee_id,
+// status, date_from, date_to and convert_to. status defaults to
+// "completed". With convert_to every amount is converted into that
+// currency, and the report fails with 422 if any rate is missing.
+type ReportHandler struct {
+	service *service.ReportService
+}
+
+func NewReportHandler(s *service.ReportService) *ReportHandler {
+	return &ReportHandler{service: s}
+}
+
+// Summary godoc
+// GET /api/v1/reports/summary?date_from=2026-01-01&date_to=2026-01-31
+// Income, expense and net per currency.
+func (h *ReportHandler) Summary(c *gin.Context) {
+	var filter domain.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := h.service.Summary(c.Request.Context(), ownerID(c), filter)
+	if errors.Is(err, service.ErrMissingExchangeRate) {
+		response.Error(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to build summary report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", summary)
+}
+
+// ByCategory godoc
+// GET /api/v1/reports/by-category?type=expense
+// Totals per category, largest first.
+func (h *ReportHandler) ByCategory(c *gin.Context) {
+	var filter domain.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.service.ByCategory(c.Request.Context(), ownerID(c), filter)
+	if errors.Is(err, service.ErrMissingExchangeRate) {
+		response.Error(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to build category report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", reports)
+}
+
+// ByTag godoc
+// GET /api/v1/reports/by-tag?type=expense
+// Totals per tag, largest first. A transaction counts towards each of its tags.
+func (h *ReportHandler) ByTag(c *gin.Context) {
+	var filter domain.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.service.ByTag(c.Request.Context(), ownerID(c), filter)
+	if errors.Is(err, service.ErrMissingExchangeRate) {
+		response.Error(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to build tag report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", reports)
+}
+
+// ByPayee godoc
+// GET /api/v1/reports/by-payee?type=expense&date_from=2026-01-01
+// Totals per payee, largest first, e.g. how much went to one shop this year.
+func (h *ReportHandler) ByPayee(c *gin.Context) {
+	var filter domain.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.service.ByPayee(c.Request.Context(), ownerID(c), filter)
+	if errors.Is(err, service.ErrMissingExchangeRate) {
+		response.Error(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to build payee report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", reports)
+}
+
+// MonthlyTrend godoc
+// GET /api/v1/reports/monthly-trend?date_from=2026-01-01
+// Income, expense and net per month.
+func (h *ReportHandler) MonthlyTrend(c *gin.Context) {
+	var filter domain.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.service.MonthlyTrend(c.Request.Context(), ownerID(c), filter)
+	if errors.Is(err, service.ErrMissingExchangeRate) {
+		response.Error(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to build monthly trend report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", reports)
+}
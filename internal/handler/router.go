@@ -5,33 +5,120 @@ import (
 	"personal-finance-backend/internal/middleware"
 	"personal-finance-backend/internal/repository"
 	"personal-finance-backend/internal/service"
+	"personal-finance-backend/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// RegisterRoutes registers all API routes.
-func RegisterRoutes(r *gin.Engine, db *pgxpool.Pool, cfg *config.Config) {
+// RegisterRoutes registers all API routes. Attachment files are kept in store.
+func RegisterRoutes(r *gin.Engine, db *pgxpool.Pool, cfg *config.Config, store storage.Storage) {
+	registerValidators()
+
 	// Init layers
 	// =========================
+	// Audit log
+	// ==========================
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := NewAuditHandler(auditService)
+	// =========================
 	// API Key management
 	// ==========================
 	apiKeyRepo := repository.NewApiKeyRepository(db)
-	apiKeyService := service.NewApiKeyService(apiKeyRepo)
+	apiKeyService := service.NewApiKeyService(apiKeyRepo, auditService, cfg.APIKeyRotationGrace)
 	apiKeyHandler := NewApiKeyHandler(apiKeyService)
 	// =========================
+	// Rate limiting
+	// ==========================
+	var rateLimiter service.RateLimiter = service.NewMemoryRateLimiter()
+	if cfg.RateLimitStore == "postgres" {
+		rateLimiter = service.NewPostgresRateLimiter(repository.NewRateLimitRepository(db))
+	}
+	// =========================
+	// Users & authentication
+	// ==========================
+	userRepo := repository.NewUserRepository(db)
+	authService := service.NewAuthService(userRepo, cfg.JWTSecret, cfg.JWTExpire, cfg.JWTRefreshTTL)
+	authHandler := NewAuthHandler(authService)
+	// =========================
 	// Categories
 	// ==========================
 	categoryRepo := repository.NewCategoryRepository(db)
-	categoryService := service.NewCategoryService(categoryRepo)
+	categoryService := service.NewCategoryService(categoryRepo, auditService)
 	categoryHandler := NewCategoryHandler(categoryService)
 	// =========================
+	// Accounts
+	// ==========================
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := service.NewAccountService(accountRepo)
+	accountHandler := NewAccountHandler(accountService)
+	// =========================
+	// Payees
+	// ==========================
+	payeeRepo := repository.NewPayeeRepository(db)
+	payeeService := service.NewPayeeService(payeeRepo, categoryRepo)
+	payeeHandler := NewPayeeHandler(payeeService)
+	// =========================
 	// Transactions
 	// ==========================
 	transactionRepo := repository.NewTransactionRepository(db)
-	transactionService := service.NewTransactionService(transactionRepo)
+	ruleRepo := repository.NewRuleRepository(db) // rules fill in new transactions
+	transactionService := service.NewTransactionService(transactionRepo, categoryRepo, accountRepo, payeeRepo, ruleRepo, auditService, cfg.BaseCurrency)
 	transactionHandler := NewTransactionHandler(transactionService)
 	// =========================
+	// Categorization rules
+	// ==========================
+	ruleService := service.NewRuleService(ruleRepo, transactionRepo, categoryRepo, payeeRepo, auditService)
+	ruleHandler := NewRuleHandler(ruleService)
+	// =========================
+	// Attachments
+	// ==========================
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	attachmentService := service.NewAttachmentService(attachmentRepo, transactionRepo, store, cfg.AttachmentMaxSize, cfg.AttachmentTypes)
+	attachmentHandler := NewAttachmentHandler(attachmentService)
+	// =========================
+	// Tags
+	// ==========================
+	tagRepo := repository.NewTagRepository(db)
+	tagService := service.NewTagService(tagRepo)
+	tagHandler := NewTagHandler(tagService)
+	// =========================
+	// Transfers
+	// ==========================
+	transferRepo := repository.NewTransferRepository(db)
+	transferService := service.NewTransferService(transferRepo, accountRepo, auditService)
+	transferHandler := NewTransferHandler(transferService)
+	// =========================
+	// Budgets
+	// ==========================
+	budgetRepo := repository.NewBudgetRepository(db)
+	budgetService := service.NewBudgetService(budgetRepo, categoryRepo)
+	budgetHandler := NewBudgetHandler(budgetService)
+	// =========================
+	// Recurring transactions
+	// ==========================
+	recurringRepo := repository.NewRecurringRepository(db)
+	recurringService := service.NewRecurringService(recurringRepo, categoryRepo, accountRepo)
+	recurringHandler := NewRecurringHandler(recurringService)
+	// =========================
+	// Exchange rates
+	// ==========================
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, cfg.BaseCurrency)
+	exchangeRateHandler := NewExchangeRateHandler(exchangeRateService)
+	// =========================
+	// Reports
+	// ==========================
+	reportRepo := repository.NewReportRepository(db)
+	reportService := service.NewReportService(reportRepo, cfg.BaseCurrency)
+	reportHandler := NewReportHandler(reportService)
+	// =========================
+	// Imports
+	// ==========================
+	importService := service.NewImportService(transactionRepo, categoryRepo, accountRepo, ruleRepo, auditService)
+	importHandler := NewImportHandler(importService)
+	// =========================
 	// Health check
 	// ==========================
 	healthHandler := NewHealthHandler()
@@ -40,7 +127,7 @@ func RegisterRoutes(r *gin.Engine, db *pgxpool.Pool, cfg *config.Config) {
 	r.GET("/health", healthHandler.Check)
 
 	// Admin routes (protected by ADMIN_API_KEY from env)
-	// Used to manage API keys (create, list, update, delete)
+	// Used to manage API keys (create, list, update, delete) and read the audit log
 	admin := r.Group("/admin/v1")
 	admin.Use(middleware.AdminAuth(cfg.AdminAPIKey))
 	{
@@ -48,28 +135,136 @@ func RegisterRoutes(r *gin.Engine, db *pgxpool.Pool, cfg *config.Config) {
 		admin.GET("/api-keys", apiKeyHandler.List)
 		admin.GET("/api-keys/:id", apiKeyHandler.GetByID)
 		admin.PATCH("/api-keys/:id", apiKeyHandler.Update)
+		admin.POST("/api-keys/:id/rotate", apiKeyHandler.Rotate)
 		admin.DELETE("/api-keys/:id", apiKeyHandler.Delete)
+
+		admin.GET("/audit", auditHandler.List)
+	}
+
+	// User authentication (public, issues JWTs)
+	auth := r.Group("/api/v1/auth")
+	{
+		auth.POST("/register", authHandler.Register)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/logout", authHandler.Logout)
+		auth.GET("/me", middleware.JWTAuth(authService), authHandler.Me)
 	}
 
-	// API routes (protected by API key from database)
-	// Used by external projects/services
+	// API routes (protected by API key from database, or a user access token)
+	// Used by external projects/services. Data is scoped to the key or user.
 	api := r.Group("/api/v1")
-	api.Use(middleware.APIKeyAuth(apiKeyService))
+	api.Use(middleware.APIKeyOrJWTAuth(middleware.APIKeyAuth(apiKeyService), middleware.JWTAuth(authService)))
+	api.Use(middleware.RateLimit(rateLimiter, cfg.RateLimitPerMinute))
 	{
 		api.GET("/ping", healthHandler.Ping)
 
+		// Each resource group requires its read scope for GET and its write
+		// scope for everything else, when the caller uses an API key
+
 		// Categories
-		api.POST("/categories", categoryHandler.Create)
-		api.GET("/categories", categoryHandler.List)
-		api.GET("/categories/:id", categoryHandler.GetByID)
-		api.PATCH("/categories/:id", categoryHandler.Update)
-		api.DELETE("/categories/:id", categoryHandler.Delete)
+		categories := api.Group("/categories", middleware.RequireScope("categories"))
+		categories.POST("", categoryHandler.Create)
+		categories.GET("", categoryHandler.List)
+		categories.GET("/:id", categoryHandler.GetByID)
+		categories.PATCH("/:id", categoryHandler.Update)
+		categories.DELETE("/:id", categoryHandler.Delete)
+		categories.POST("/:id/restore", categoryHandler.Restore)
+		categories.POST("/:id/merge", categoryHandler.Merge)
+
+		// Accounts
+		accounts := api.Group("/accounts", middleware.RequireScope("accounts"))
+		accounts.POST("", accountHandler.Create)
+		accounts.GET("", accountHandler.List)
+		accounts.GET("/:id", accountHandler.GetByID)
+		accounts.PATCH("/:id", accountHandler.Update)
+		accounts.DELETE("/:id", accountHandler.Delete)
+
+		// Payees
+		payees := api.Group("/payees", middleware.RequireScope("payees"))
+		payees.POST("", payeeHandler.Create)
+		payees.GET("", payeeHandler.List)
+		payees.GET("/:id", payeeHandler.GetByID)
+		payees.PATCH("/:id", payeeHandler.Update)
+		payees.DELETE("/:id", payeeHandler.Delete)
 
 		// Transactions
-		api.POST("/transactions", transactionHandler.Create)
-		api.GET("/transactions", transactionHandler.List)
-		api.GET("/transactions/:id", transactionHandler.GetByID)
-		api.PATCH("/transactions/:id", transactionHandler.Update)
-		api.DELETE("/transactions/:id", transactionHandler.Delete)
+		transactions := api.Group("/transactions", middleware.RequireScope("transactions"))
+		transactions.POST("", transactionHandler.Create)
+		transactions.GET("", transactionHandler.List)
+		transactions.GET("/export.csv", transactionHandler.Export)
+		transactions.GET("/:id", transactionHandler.GetByID)
+		transactions.PATCH("/:id", transactionHandler.Update)
+		transactions.DELETE("/:id", transactionHandler.Delete)
+		transactions.POST("/:id/restore", transactionHandler.Restore)
+		transactions.POST("/:id/attachments", attachmentHandler.Create)
+		transactions.GET("/:id/attachments", attachmentHandler.List)
+		transactions.GET("/:id/attachments/:attachment_id", attachmentHandler.Download)
+		transactions.DELETE("/:id/attachments/:attachment_id", attachmentHandler.Delete)
+
+		// Tags
+		tags := api.Group("/tags", middleware.RequireScope("tags"))
+		tags.POST("", tagHandler.Create)
+		tags.GET("", tagHandler.List)
+		tags.GET("/:id", tagHandler.GetByID)
+		tags.PATCH("/:id", tagHandler.Update)
+		tags.DELETE("/:id", tagHandler.Delete)
+
+		// Categorization rules
+		rules := api.Group("/rules", middleware.RequireScope("rules"))
+		rules.POST("", ruleHandler.Create)
+		rules.GET("", ruleHandler.List)
+		rules.POST("/apply", middleware.RequireScope("transactions"), ruleHandler.Apply) // rewrites transactions too
+		rules.GET("/:id", ruleHandler.GetByID)
+		rules.PATCH("/:id", ruleHandler.Update)
+		rules.DELETE("/:id", ruleHandler.Delete)
+
+		// Transfers are pairs of transactions
+		transfers := api.Group("/transfers", middleware.RequireScope("transactions"))
+		transfers.POST("", transferHandler.Create)
+		transfers.GET("", transferHandler.List)
+		transfers.GET("/:id", transferHandler.GetByID)
+		transfers.PATCH("/:id", transferHandler.Update)
+		transfers.DELETE("/:id", transferHandler.Delete)
+		transfers.POST("/:id/restore", transferHandler.Restore)
+
+		// Budgets
+		budgets := api.Group("/budgets", middleware.RequireScope("budgets"))
+		budgets.POST("", budgetHandler.Create)
+		budgets.GET("", budgetHandler.List)
+		budgets.GET("/status", budgetHandler.Status)
+		budgets.GET("/:id", budgetHandler.GetByID)
+		budgets.PATCH("/:id", budgetHandler.Update)
+		budgets.DELETE("/:id", budgetHandler.Delete)
+
+		// Recurring transactions
+		recurring := api.Group("/recurring", middleware.RequireScope("recurring"))
+		recurring.POST("", recurringHandler.Create)
+		recurring.GET("", recurringHandler.List)
+		recurring.GET("/:id", recurringHandler.GetByID)
+		recurring.GET("/:id/preview", recurringHandler.Preview)
+		recurring.PATCH("/:id", recurringHandler.Update)
+		recurring.DELETE("/:id", recurringHandler.Delete)
+
+		// Exchange rates
+		exchangeRates := api.Group("/exchange-rates", middleware.RequireScope("exchange_rates"))
+		exchangeRates.POST("", exchangeRateHandler.Create)
+		exchangeRates.POST("/bulk", exchangeRateHandler.Bulk)
+		exchangeRates.GET("", exchangeRateHandler.List)
+		exchangeRates.GET("/:id", exchangeRateHandler.GetByID)
+		exchangeRates.PATCH("/:id", exchangeRateHandler.Update)
+		exchangeRates.DELETE("/:id", exchangeRateHandler.Delete)
+
+		// Imports create transactions
+		imports := api.Group("/imports", middleware.RequireScope("transactions"))
+		imports.POST("", importHandler.Create)
+
+		// Reports
+		reports := api.Group("/reports", middleware.RequireScope("reports"))
+		reports.GET("/summary", reportHandler.Summary)
+		reports.GET("/by-category", reportHandler.ByCategory)
+		reports.GET("/by-tag", reportHandler.ByTag)
+		reports.GET("/by-payee", reportHandler.ByPayee)
+		reports.GET("/monthly-trend", reportHandler.MonthlyTrend)
 	}
 }
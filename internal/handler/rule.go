@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// RuleHandler manages auto-categorization rules. Rules fill in
+// transactions created without a category, through the API or an import.
+type RuleHandler struct {
+	service *service.RuleService
+}
+
+func NewRuleHandler(s *service.RuleService) *RuleHandler {
+	return &RuleHandler{service: s}
+}
+
+// isRuleError reports whether err is a problem with the rule in the request.
+func isRuleError(err error) bool {
+	return errors.Is(err, service.ErrRuleNoConditions) || errors.Is(err, service.ErrRuleNoActions) ||
+		errors.Is(err, service.ErrRuleRegex) || errors.Is(err, service.ErrRuleAmountRange) ||
+		errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrUnknownPayee) ||
+		errors.Is(err, service.ErrRuleCategoryType)
+}
+
+// Create godoc
+// POST /api/v1/rules
+//
+//	{ "name": "Grab rides", "priority": 10,
+//	  "conditions": { "description_regex": "^GRAB\\*", "type": "expense" },
+//	  "actions": { "category_id": "...", "payee_id": "...", "tags": ["transport"] } }
+func (h *RuleHandler) Create(c *gin.Context) {
+	var req domain.CreateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if isRuleError(err) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create rule")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Rule created", rule)
+}
+
+func (h *RuleHandler) List(c *gin.Context) {
+	rules, err := h.service.GetAll(c.Request.Context(), ownerID(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list rules")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", rules)
+}
+
+func (h *RuleHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	rule, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Rule not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", rule)
+}
+
+func (h *RuleHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Rule not found")
+		return
+	}
+	if isRuleError(err) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Rule updated", rule)
+}
+
+func (h *RuleHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Rule not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Rule deleted", nil)
+}
+
+// Apply godoc
+// POST /api/v1/rules/apply
+//
+//	{ "date_from": "2026-01-01", "date_to": "2026-03-31", "dry_run": true }
+//
+// Re-runs the enabled rules over existing transactions in the range. A dry
+// run lists the changes without saving them.
+func (h *RuleHandler) Apply(c *gin.Context) {
+	var req domain.ApplyRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.service.Apply(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrDateRange) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to apply rules")
+		return
+	}
+
+	if req.DryRun {
+		response.Success(c, http.StatusOK, "Dry run, nothing was changed", result)
+		return
+	}
+	response.Success(c, http.StatusOK, "Rules applied", result)
+}
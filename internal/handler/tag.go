@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// TagHandler manages tags. Tags are also created on the fly when a
+// transaction is saved with a name that doesn't exist yet.
+type TagHandler struct {
+	service *service.TagService
+}
+
+func NewTagHandler(s *service.TagService) *TagHandler {
+	return &TagHandler{service: s}
+}
+
+func (h *TagHandler) Create(c *gin.Context) {
+	var req domain.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrTagNameEmpty) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A tag with this name already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create tag")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Tag created", tag)
+}
+
+func (h *TagHandler) List(c *gin.Context) {
+	tags, err := h.service.GetAll(c.Request.Context(), ownerID(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list tags")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", tags)
+}
+
+func (h *TagHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	tag, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", tag)
+}
+
+// Update godoc
+// PATCH /api/v1/tags/:id
+// Renames the tag on every transaction carrying it.
+func (h *TagHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Tag not found")
+		return
+	}
+	if errors.Is(err, service.ErrTagNameEmpty) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if isUniqueViolation(err) {
+		response.Error(c, http.StatusConflict, "A tag with this name already exists")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update tag")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tag updated", tag)
+}
+
+// Delete godoc
+// DELETE /api/v1/tags/:id
+// Removes the tag from every transaction carrying it.
+func (h *TagHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Tag not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete tag")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tag deleted", nil)
+}
@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"encoding/csv"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"personal-finance-backend/internal/domain"
 	"personal-finance-backend/internal/service"
 	"personal-finance-backend/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 type TransactionHandler struct {
@@ -25,7 +31,14 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.service.Create(c.Request.Context(), req)
+	tx, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, domain.ErrAmountPrecision) ||
+		errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrUnknownAccount) ||
+		errors.Is(err, service.ErrUnknownPayee) || errors.Is(err, service.ErrCategoryRequired) ||
+		errors.Is(err, service.ErrSplitSum) || errors.Is(err, service.ErrCategoryType) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create transaction: "+err.Error())
 		return
@@ -41,7 +54,7 @@ func (h *TransactionHandler) List(c *gin.Context) {
 		return
 	}
 
-	transactions, total, err := h.service.GetAll(c.Request.Context(), filter)
+	transactions, total, err := h.service.GetAll(c.Request.Context(), ownerID(c), filter)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to list transactions")
 		return
@@ -55,10 +68,118 @@ func (h *TransactionHandler) List(c *gin.Context) {
 	})
 }
 
+var exportHeader = []string{
+	"id", "date", "type", "category_id", "category_name", "account_id", "account_name",
+	"amount", "currency", "description", "status", "transfer_id", "transfer_direction", "created_at",
+	"tags", // comma-separated
+	"payee_id", "payee_name",
+}
+
+// Export godoc
+// GET /api/v1/transactions/export.csv
+// Takes the same filters as List but returns every matching row as CSV.
+// Rows are written as they are read from the database. With convert_to,
+// converted_amount and converted_currency columns are appended.
+func (h *TransactionHandler) Export(c *gin.Context) {
+	var filter domain.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	header, record := exportHeader, transactionCSVRecord
+	if filter.ConvertTo != "" {
+		header = append(header[:len(header):len(header)], "converted_amount", "converted_currency")
+		record = convertedCSVRecord
+	}
+
+	w := csv.NewWriter(c.Writer)
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="transactions.csv"`)
+		c.Status(http.StatusOK)
+		return w.Write(header)
+	}
+
+	rowCount := 0
+	err := h.service.Export(c.Request.Context(), ownerID(c), filter, func(t domain.Transaction) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := w.Write(record(t)); err != nil {
+			return err
+		}
+		// Push rows to the client regularly instead of buffering the whole export
+		if rowCount++; rowCount%500 == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+		return w.Error()
+	})
+	if err != nil && !started {
+		response.Error(c, http.StatusInternalServerError, "Failed to export transactions")
+		return
+	}
+	if err != nil {
+		// Headers are already sent, all we can do is cut the response short
+		log.Println("Transaction export aborted:", err)
+		c.Abort()
+		return
+	}
+
+	if !started {
+		if err := start(); err != nil {
+			log.Println("Transaction export aborted:", err)
+			return
+		}
+	}
+	w.Flush()
+}
+
+func transactionCSVRecord(t domain.Transaction) []string {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	return []string{
+		t.ID,
+		t.Date,
+		t.Type,
+		deref(t.CategoryID),
+		t.CategoryName,
+		deref(t.AccountID),
+		deref(t.AccountName),
+		domain.FormatAmount(t.Amount, t.Currency),
+		t.Currency,
+		deref(t.Description),
+		t.Status,
+		deref(t.TransferID),
+		deref(t.Direction),
+		t.CreatedAt.Format(time.RFC3339),
+		strings.Join(t.Tags, ","),
+		deref(t.PayeeID),
+		deref(t.PayeeName),
+	}
+}
+
+func convertedCSVRecord(t domain.Transaction) []string {
+	converted := ""
+	if t.ConvertedAmount != nil {
+		converted = domain.FormatAmount(*t.ConvertedAmount, t.ConvertedCurrency)
+	}
+	return append(transactionCSVRecord(t), converted, t.ConvertedCurrency)
+}
+
 func (h *TransactionHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	tx, err := h.service.GetByID(c.Request.Context(), id)
+	tx, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
 	if err != nil {
 		response.Error(c, http.StatusNotFound, "Transaction not found")
 		return
@@ -76,7 +197,18 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.service.Update(c.Request.Context(), id, req)
+	tx, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Transaction not found")
+		return
+	}
+	if errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, service.ErrTransferLeg) || errors.Is(err, domain.ErrAmountPrecision) ||
+		errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrUnknownAccount) ||
+		errors.Is(err, service.ErrUnknownPayee) || errors.Is(err, service.ErrSplitSum) ||
+		errors.Is(err, service.ErrCategoryType) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update transaction")
 		return
@@ -88,10 +220,38 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 func (h *TransactionHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Transaction not found")
+		return
+	}
+	if errors.Is(err, service.ErrTransferLeg) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete transaction")
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Transaction deleted", nil)
 }
+
+// Restore godoc
+// POST /api/v1/transactions/:id/restore
+// Brings back a deleted transaction.
+func (h *TransactionHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	tx, err := h.service.Restore(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Deleted transaction not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to restore transaction")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Transaction restored", tx)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type TransferHandler struct {
+	service *service.TransferService
+}
+
+func NewTransferHandler(s *service.TransferService) *TransferHandler {
+	return &TransferHandler{service: s}
+}
+
+// Create godoc
+// POST /api/v1/transfers
+// Body: { "from_account_id": "...", "to_account_id": "...", "amount": 500000 }
+// Response: both legs of the transfer
+func (h *TransferHandler) Create(c *gin.Context) {
+	var req domain.CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transfer, err := h.service.Create(c.Request.Context(), ownerID(c), req)
+	if errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, service.ErrSameAccount) || errors.Is(err, domain.ErrAmountPrecision) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUnknownAccount) {
+		response.Error(c, http.StatusBadRequest, "Account not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create transfer")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Transfer created", transfer)
+}
+
+// List godoc
+// GET /api/v1/transfers?account_id=...&date_from=...&date_to=...
+func (h *TransferHandler) List(c *gin.Context) {
+	var filter domain.TransferFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transfers, total, err := h.service.GetAll(c.Request.Context(), ownerID(c), filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list transfers")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", gin.H{
+		"transfers": transfers,
+		"total":     total,
+		"page":      filter.Page,
+		"limit":     filter.Limit,
+	})
+}
+
+// GetByID godoc
+// GET /api/v1/transfers/:id
+func (h *TransferHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	transfer, err := h.service.GetByID(c.Request.Context(), ownerID(c), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Transfer not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OK", transfer)
+}
+
+// Update godoc
+// PATCH /api/v1/transfers/:id
+// Body: { "amount": 750000 }
+func (h *TransferHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.UpdateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transfer, err := h.service.Update(c.Request.Context(), ownerID(c), id, req)
+	if errors.Is(err, service.ErrCurrencyMismatch) || errors.Is(err, service.ErrSameAccount) || errors.Is(err, domain.ErrAmountPrecision) ||
+		errors.Is(err, service.ErrTransferAccountMissing) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, service.ErrUnknownAccount) {
+		response.Error(c, http.StatusNotFound, "Transfer or account not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update transfer")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Transfer updated", transfer)
+}
+
+// Delete godoc
+// DELETE /api/v1/transfers/:id
+func (h *TransferHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.service.Delete(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Transfer not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete transfer")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Transfer deleted", nil)
+}
+
+// Restore godoc
+// POST /api/v1/transfers/:id/restore
+// Brings back both legs of a deleted transfer.
+func (h *TransferHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	transfer, err := h.service.Restore(c.Request.Context(), ownerID(c), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		response.Error(c, http.StatusNotFound, "Deleted transfer not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to restore transfer")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Transfer restored", transfer)
+}
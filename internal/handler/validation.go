@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"reflect"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// registerValidators teaches gin's validator about the custom types used in
+// request structs, so tags like `binding:"required,gt=0"` work on them, and
+// registers the custom tags such as apikey_scope.
+func registerValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Decimals are compared by value; the float is only used for the
+	// comparison, the request keeps the exact amount.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if d, ok := field.Interface().(decimal.Decimal); ok {
+			f, _ := d.Float64()
+			return f
+		}
+		return nil
+	}, decimal.Decimal{})
+
+	v.RegisterValidation("apikey_scope", func(fl validator.FieldLevel) bool {
+		return domain.IsApiKeyScope(fl.Field().String())
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/subtle"
 	"net/http"
 
+	"personal-finance-backend/internal/domain"
 	"personal-finance-backend/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -27,6 +28,8 @@ func AdminAuth(adminKey string) gin.HandlerFunc {
 			return
 		}
 
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(),
+			domain.Actor{Type: domain.ActorAdmin, Name: "admin"}))
 		c.Next()
 	}
 }
@@ -4,6 +4,7 @@ package middleware
 import (
 	"net/http"
 
+	"personal-finance-backend/internal/domain"
 	"personal-finance-backend/internal/service"
 	"personal-finance-backend/pkg/response"
 
@@ -23,7 +24,7 @@ func APIKeyAuth(apiKeyService *service.ApiKeyService) gin.HandlerFunc {
 
 		apiKey, err := apiKeyService.ValidateKey(c.Request.Context(), key)
 		if err != nil {
-			response.Error(c, http.StatusUnauthorized, "Invalid or inactive API key")
+			response.Error(c, http.StatusUnauthorized, "Invalid, inactive or expired API key")
 			c.Abort()
 			return
 		}
@@ -31,6 +32,37 @@ func APIKeyAuth(apiKeyService *service.ApiKeyService) gin.HandlerFunc {
 		// Store API key info in context for downstream handlers
 		c.Set("api_key_id", apiKey.ID)
 		c.Set("api_key_name", apiKey.Name)
+		c.Set("api_key", apiKey)
+		// Data created through an API key belongs to that key
+		c.Set("owner_id", apiKey.ID)
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(),
+			domain.Actor{Type: domain.ActorAPIKey, ID: apiKey.ID, Name: apiKey.Name}))
+		c.Next()
+	}
+}
+
+// RequireScope returns a middleware that lets API keys through only when
+// they have the resource's read scope (GET and HEAD) or write scope (every
+// other method), e.g. "transactions:read". Requests signed in with a user
+// access token are not limited by scopes.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := c.Get("api_key")
+		if !ok {
+			c.Next()
+			return
+		}
+
+		scope := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = resource + ":read"
+		}
+		if !v.(*domain.ApiKey).HasScope(scope) {
+			response.Error(c, http.StatusForbidden, "API key is missing the "+scope+" scope")
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWTAuth returns a middleware that validates the "Authorization: Bearer"
+// access token issued by /api/v1/auth.
+func JWTAuth(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			response.Error(c, http.StatusUnauthorized, "Missing bearer token")
+			c.Abort()
+			return
+		}
+
+		userID, err := authService.ValidateAccessToken(token)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, "Invalid or expired access token")
+			c.Abort()
+			return
+		}
+
+		// Store the user ID in context for downstream handlers
+		c.Set("user_id", userID)
+		c.Set("owner_id", userID)
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(),
+			domain.Actor{Type: domain.ActorUser, ID: userID}))
+		c.Next()
+	}
+}
+
+// APIKeyOrJWTAuth accepts either credential: requests carrying a bearer
+// token and no X-API-Key go through jwtAuth, everything else through
+// apiKeyAuth. Both set "owner_id", the principal that owns the data.
+func APIKeyOrJWTAuth(apiKeyAuth, jwtAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") == "" && c.GetHeader("Authorization") != "" {
+			jwtAuth(c)
+			return
+		}
+		apiKeyAuth(c)
+	}
+}
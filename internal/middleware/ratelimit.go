@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/service"
+	"personal-finance-backend/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit returns a middleware that limits each API key to its
+// rate_limit_per_minute, or defaultLimit when the key has none, and sets the
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (Unix time
+// at which the full limit is available again) headers. Requests over the
+// limit get 429 with Retry-After. Must run after APIKeyAuth; requests
+// without an API key aren't limited. A defaultLimit of 0 only limits keys
+// with their own limit.
+func RateLimit(limiter service.RateLimiter, defaultLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, ok := c.Get("api_key")
+		if !ok {
+			c.Next()
+			return
+		}
+		apiKey := v.(*domain.ApiKey)
+
+		limit := defaultLimit
+		if apiKey.RateLimit != nil {
+			limit = *apiKey.RateLimit
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		rl, err := limiter.Take(c.Request.Context(), apiKey.ID, limit)
+		if err != nil {
+			// Don't turn a limiter outage into an API outage
+			log.Println("rate limit:", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rl.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(rl.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(float64(rl.Reset.UnixMilli())/1000)), 10))
+		if !rl.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rl.RetryAfter.Seconds()))))
+			response.Error(c, http.StatusTooManyRequests, "Rate limit exceeded, retry later")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// accountSelect computes the current balance of each account from its
+// opening balance and the completed transactions booked against it.
+// Transfer legs move money out of or into the account.
+const accountSelect = `
+	SELECT a.id, a.name, a.type, a.currency, a.opening_balance,
+	       a.opening_balance + COALESCE(SUM(
+	           CASE t.type
+	               WHEN 'income' THEN t.amount
+	               WHEN 'expense' THEN -t.amount
+	               WHEN 'transfer' THEN
+	                   CASE t.transfer_direction WHEN 'in' THEN t.amount ELSE -t.amount END
+	               ELSE 0
+	           END), 0) AS balance,
+	       a.created_at, a.updated_at
+	FROM accounts a
+	LEFT JOIN transactions t ON t.account_id = a.id AND t.status = 'completed' AND t.deleted_at IS NULL`
+
+type AccountRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAccountRepository(db *pgxpool.Pool) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+func (r *AccountRepository) Create(ctx context.Context, ownerID string, req domain.CreateAccountRequest) (*domain.Account, error) {
+	if req.Currency == "" {
+		req.Currency = "IDR"
+	}
+
+	var id string
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO accounts (name, type, currency, opening_balance, owner_id)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id`,
+		req.Name, req.Type, req.Currency, req.OpeningBalance, ownerID,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *AccountRepository) GetAll(ctx context.Context, ownerID string) ([]domain.Account, error) {
+	rows, err := r.db.Query(ctx, accountSelect+`
+		WHERE a.owner_id = $1
+		GROUP BY a.id
+		ORDER BY a.name`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []domain.Account
+	for rows.Next() {
+		var a domain.Account
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Currency, &a.OpeningBalance,
+			&a.Balance, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+func (r *AccountRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Account, error) {
+	var a domain.Account
+	err := r.db.QueryRow(ctx, accountSelect+`
+		WHERE a.id = $1 AND a.owner_id = $2
+		GROUP BY a.id`, id, ownerID,
+	).Scan(&a.ID, &a.Name, &a.Type, &a.Currency, &a.OpeningBalance,
+		&a.Balance, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AccountRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateAccountRequest) (*domain.Account, error) {
+	if req.Name != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE accounts SET name = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Name, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Type != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE accounts SET type = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Type, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.OpeningBalance != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE accounts SET opening_balance = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.OpeningBalance, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Delete removes one of the owner's accounts. It fails with a foreign key
+// violation while transactions or recurring rules still use the account.
+func (r *AccountRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM accounts WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"personal-finance-backend/internal/domain"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,13 +19,38 @@ func NewApiKeyRepository(db *pgxpool.Pool) *ApiKeyRepository {
 	return &ApiKeyRepository{db: db}
 }
 
-func (r *ApiKeyRepository) Create(ctx context.Context, name, key string) (*domain.ApiKey, error) {
+// apiKeyColumns selects a key with the status of its current and previous
+// secret, judged by the database clock like ValidateKey.
+const apiKeyColumns = `id, name, key_prefix, scopes, is_active, expires_at, rate_limit_per_minute,
+	CASE WHEN NOT is_active THEN 'inactive' WHEN expires_at <= now() THEN 'expired' ELSE 'active' END,
+	created_at, last_used_at, rotated_at, previous_key_prefix, previous_expires_at,
+	CASE WHEN NOT is_active THEN 'inactive' WHEN previous_expires_at <= now() THEN 'expired' ELSE 'active' END`
+
+// scanApiKey reads a row of apiKeyColumns.
+func scanApiKey(row pgx.Row, k *domain.ApiKey) error {
+	var prevPrefix *string
+	var prevExpiresAt *time.Time
+	var prevStatus string
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.IsActive, &k.ExpiresAt, &k.RateLimit, &k.Status,
+		&k.CreatedAt, &k.LastUsedAt, &k.RotatedAt, &prevPrefix, &prevExpiresAt, &prevStatus)
+	if err != nil {
+		return err
+	}
+	if prevPrefix != nil && prevExpiresAt != nil {
+		k.PreviousKey = &domain.PreviousApiKey{Prefix: *prevPrefix, ExpiresAt: *prevExpiresAt, Status: prevStatus}
+	}
+	return nil
+}
+
+// Create stores a key by its hash; the plaintext key is never saved.
+func (r *ApiKeyRepository) Create(ctx context.Context, keyHash, keyPrefix string, req domain.CreateApiKeyRequest) (*domain.ApiKey, error) {
 	var apiKey domain.ApiKey
-	err := r.db.QueryRow(ctx,
-		`INSERT INTO api_keys (name, key) VALUES ($1, $2)
-		 RETURNING id, name, key, is_active, created_at, last_used_at`,
-		name, key,
-	).Scan(&apiKey.ID, &apiKey.Name, &apiKey.Key, &apiKey.IsActive, &apiKey.CreatedAt, &apiKey.LastUsedAt)
+	err := scanApiKey(r.db.QueryRow(ctx,
+		`INSERT INTO api_keys (name, key_hash, key_prefix, scopes, expires_at, rate_limit_per_minute)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+apiKeyColumns,
+		req.Name, keyHash, keyPrefix, req.Scopes, req.ExpiresAt, req.RateLimit,
+	), &apiKey)
 	if err != nil {
 		return nil, err
 	}
@@ -32,8 +59,7 @@ func (r *ApiKeyRepository) Create(ctx context.Context, name, key string) (*domai
 
 func (r *ApiKeyRepository) GetAll(ctx context.Context) ([]domain.ApiKey, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT id, name, is_active, created_at, last_used_at
-		 FROM api_keys ORDER BY created_at DESC`,
+		`SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`,
 	)
 	if err != nil {
 		return nil, err
@@ -43,7 +69,7 @@ func (r *ApiKeyRepository) GetAll(ctx context.Context) ([]domain.ApiKey, error)
 	var keys []domain.ApiKey
 	for rows.Next() {
 		var k domain.ApiKey
-		if err := rows.Scan(&k.ID, &k.Name, &k.IsActive, &k.CreatedAt, &k.LastUsedAt); err != nil {
+		if err := scanApiKey(rows, &k); err != nil {
 			return nil, err
 		}
 		keys = append(keys, k)
@@ -53,10 +79,9 @@ func (r *ApiKeyRepository) GetAll(ctx context.Context) ([]domain.ApiKey, error)
 
 func (r *ApiKeyRepository) GetByID(ctx context.Context, id string) (*domain.ApiKey, error) {
 	var k domain.ApiKey
-	err := r.db.QueryRow(ctx,
-		`SELECT id, name, is_active, created_at, last_used_at
-		 FROM api_keys WHERE id = $1`, id,
-	).Scan(&k.ID, &k.Name, &k.IsActive, &k.CreatedAt, &k.LastUsedAt)
+	err := scanApiKey(r.db.QueryRow(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id,
+	), &k)
 	if err != nil {
 		return nil, err
 	}
@@ -72,16 +97,30 @@ func (r *ApiKeyRepository) Update(ctx context.Context, id string, req domain.Upd
 			return nil, err
 		}
 	}
+	if req.Scopes != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE api_keys SET scopes = $1 WHERE id = $2`, *req.Scopes, id); err != nil {
+			return nil, err
+		}
+	}
 	if req.IsActive != nil {
 		if _, err := r.db.Exec(ctx, `UPDATE api_keys SET is_active = $1 WHERE id = $2`, *req.IsActive, id); err != nil {
 			return nil, err
 		}
 	}
+	if req.ExpiresAt != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE api_keys SET expires_at = $1 WHERE id = $2`, *req.ExpiresAt, id); err != nil {
+			return nil, err
+		}
+	}
+	if req.RateLimit != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE api_keys SET rate_limit_per_minute = $1 WHERE id = $2`, *req.RateLimit, id); err != nil {
+			return nil, err
+		}
+	}
 
-	err := r.db.QueryRow(ctx,
-		`SELECT id, name, is_active, created_at, last_used_at
-		 FROM api_keys WHERE id = $1`, id,
-	).Scan(&k.ID, &k.Name, &k.IsActive, &k.CreatedAt, &k.LastUsedAt)
+	err := scanApiKey(r.db.QueryRow(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id,
+	), &k)
 	if err != nil {
 		return nil, err
 	}
@@ -89,19 +128,53 @@ func (r *ApiKeyRepository) Update(ctx context.Context, id string, req domain.Upd
 }
 
 func (r *ApiKeyRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM api_keys WHERE id = $1`, id)
-	return err
+	tag, err := r.db.Exec(ctx, `DELETE FROM api_keys WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Rotate replaces the secret of a key. The old secret stays valid until
+// previousExpiresAt, but never past the key's own expiry; a secret still in
+// the grace period of an earlier rotation stops working right away.
+func (r *ApiKeyRepository) Rotate(ctx context.Context, id, keyHash, keyPrefix string, previousExpiresAt time.Time, expiresAt *time.Time) (*domain.ApiKey, error) {
+	var k domain.ApiKey
+	err := scanApiKey(r.db.QueryRow(ctx,
+		`UPDATE api_keys SET
+		     previous_key_hash = key_hash,
+		     previous_key_prefix = key_prefix,
+		     previous_expires_at = LEAST($4::timestamptz, COALESCE(expires_at, 'infinity')),
+		     key_hash = $2,
+		     key_prefix = $3,
+		     expires_at = COALESCE($5, expires_at),
+		     rotated_at = now()
+		 WHERE id = $1
+		 RETURNING `+apiKeyColumns,
+		id, keyHash, keyPrefix, previousExpiresAt, expiresAt,
+	), &k)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
 }
 
-// ValidateKey checks if a key exists and is active. Updates last_used_at.
-func (r *ApiKeyRepository) ValidateKey(ctx context.Context, key string) (*domain.ApiKey, error) {
+// ValidateKey checks if a key, given by its hash, exists, is active and
+// hasn't expired, either as the current secret or as the previous one
+// within its grace period. Updates last_used_at.
+func (r *ApiKeyRepository) ValidateKey(ctx context.Context, keyHash string) (*domain.ApiKey, error) {
 	var k domain.ApiKey
-	err := r.db.QueryRow(ctx,
+	err := scanApiKey(r.db.QueryRow(ctx,
 		`UPDATE api_keys SET last_used_at = now()
-		 WHERE key = $1 AND is_active = true
-		 RETURNING id, name, is_active, created_at, last_used_at`,
-		key,
-	).Scan(&k.ID, &k.Name, &k.IsActive, &k.CreatedAt, &k.LastUsedAt)
+		 WHERE is_active = true
+		   AND ((key_hash = $1 AND (expires_at IS NULL OR expires_at > now()))
+		     OR (previous_key_hash = $1 AND previous_expires_at > now()))
+		 RETURNING `+apiKeyColumns,
+		keyHash,
+	), &k)
 	if err != nil {
 		return nil, err
 	}
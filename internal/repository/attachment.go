@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const attachmentColumns = `a.id, a.transaction_id, a.filename, a.content_type, a.size, a.sha256, a.storage_key, a.created_at`
+
+func scanAttachment(row pgx.Row, a *domain.Attachment) error {
+	return row.Scan(&a.ID, &a.TransactionID, &a.Filename, &a.ContentType, &a.Size, &a.SHA256, &a.StorageKey, &a.CreatedAt)
+}
+
+// AttachmentRepository stores attachment metadata. Attachments are reached
+// through their transaction, so only those of the owner's live transactions
+// can be read or deleted.
+type AttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttachmentRepository(db *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, a domain.Attachment) (*domain.Attachment, error) {
+	err := scanAttachment(r.db.QueryRow(ctx,
+		`INSERT INTO attachments AS a (transaction_id, filename, content_type, size, sha256, storage_key)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+attachmentColumns,
+		a.TransactionID, a.Filename, a.ContentType, a.Size, a.SHA256, a.StorageKey,
+	), &a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepository) GetAll(ctx context.Context, ownerID, transactionID string) ([]domain.Attachment, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+attachmentColumns+`
+		 FROM attachments a JOIN transactions t ON t.id = a.transaction_id
+		 WHERE a.transaction_id = $1 AND t.owner_id = $2 AND t.deleted_at IS NULL
+		 ORDER BY a.created_at`, transactionID, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []domain.Attachment{}
+	for rows.Next() {
+		var a domain.Attachment
+		if err := scanAttachment(rows, &a); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, ownerID, transactionID, id string) (*domain.Attachment, error) {
+	var a domain.Attachment
+	err := scanAttachment(r.db.QueryRow(ctx,
+		`SELECT `+attachmentColumns+`
+		 FROM attachments a JOIN transactions t ON t.id = a.transaction_id
+		 WHERE a.id = $1 AND a.transaction_id = $2 AND t.owner_id = $3 AND t.deleted_at IS NULL`, id, transactionID, ownerID,
+	), &a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Delete removes an attachment's metadata and returns it, so that the caller
+// can remove the file.
+func (r *AttachmentRepository) Delete(ctx context.Context, ownerID, transactionID, id string) (*domain.Attachment, error) {
+	var a domain.Attachment
+	err := scanAttachment(r.db.QueryRow(ctx,
+		`DELETE FROM attachments a USING transactions t
+		 WHERE t.id = a.transaction_id AND a.id = $1 AND a.transaction_id = $2 AND t.owner_id = $3 AND t.deleted_at IS NULL
+		 RETURNING `+attachmentColumns, id, transactionID, ownerID,
+	), &a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// nullableJSON stores an empty snapshot as NULL.
+func nullableJSON(b json.RawMessage) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+func (r *AuditRepository) Create(ctx context.Context, e domain.AuditEntry) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO audit_log (actor_type, actor_id, actor_name, action, entity_type, entity_id, before, after)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.ActorType, e.ActorID, e.ActorName, e.Action, e.EntityType, e.EntityID, nullableJSON(e.Before), nullableJSON(e.After),
+	)
+	return err
+}
+
+func (r *AuditRepository) GetAll(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int, error) {
+	// Set defaults
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	offset := (filter.Page - 1) * filter.Limit
+
+	// A plain date as the upper bound includes that whole day
+	toCondition := "created_at <= $%d::timestamptz"
+	if len(filter.To) == len("2006-01-02") {
+		toCondition = "created_at < $%d::date + 1"
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, c := range []struct {
+		condition, value string
+	}{
+		{"entity_type = $%d", filter.EntityType},
+		{"entity_id = $%d", filter.EntityID},
+		{"actor_type = $%d", filter.ActorType},
+		{"actor_id = $%d", filter.ActorID},
+		{"action = $%d", filter.Action},
+		{"created_at >= $%d::timestamptz", filter.From},
+		{toCondition, filter.To},
+	} {
+		if c.value != "" {
+			args = append(args, c.value)
+			conditions = append(conditions, fmt.Sprintf(c.condition, len(args)))
+		}
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_log %s`, whereClause)
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, actor_type, actor_id, actor_name, action, entity_type, entity_id, before, after, created_at
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d`,
+		whereClause, len(args)+1, len(args)+2,
+	)
+	args = append(args, filter.Limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		if err := rows.Scan(&e.ID, &e.ActorType, &e.ActorID, &e.ActorName, &e.Action, &e.EntityType, &e.EntityID,
+			&e.Before, &e.After, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type BudgetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBudgetRepository(db *pgxpool.Pool) *BudgetRepository {
+	return &BudgetRepository{db: db}
+}
+
+func (r *BudgetRepository) Create(ctx context.Context, ownerID string, req domain.CreateBudgetRequest) (*domain.Budget, error) {
+	// Set defaults
+	if req.Currency == "" {
+		req.Currency = "IDR"
+	}
+	if req.StartMonth == "" {
+		req.StartMonth = time.Now().Format("2006-01")
+	}
+
+	var id string
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO budgets (category_id, include_subcategories, amount, currency, start_month, rollover, owner_id)
+		 VALUES ($1, $2, $3, $4, to_date($5, 'YYYY-MM'), $6, $7)
+		 RETURNING id`,
+		req.CategoryID, req.IncludeSubcategories, req.Amount, req.Currency, req.StartMonth, req.Rollover, ownerID,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *BudgetRepository) GetAll(ctx context.Context, ownerID string) ([]domain.Budget, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT b.id, b.category_id, c.name, b.include_subcategories, b.amount, b.currency, to_char(b.start_month, 'YYYY-MM'),
+		        b.rollover, b.created_at, b.updated_at
+		 FROM budgets b
+		 JOIN categories c ON c.id = b.category_id
+		 WHERE b.owner_id = $1 AND c.deleted_at IS NULL
+		 ORDER BY c.name`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []domain.Budget
+	for rows.Next() {
+		var b domain.Budget
+		if err := rows.Scan(&b.ID, &b.CategoryID, &b.CategoryName, &b.IncludeSubcategories, &b.Amount, &b.Currency,
+			&b.StartMonth, &b.Rollover, &b.CreatedAt, &b.UpdatedAt); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, nil
+}
+
+func (r *BudgetRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Budget, error) {
+	var b domain.Budget
+	err := r.db.QueryRow(ctx,
+		`SELECT b.id, b.category_id, c.name, b.include_subcategories, b.amount, b.currency, to_char(b.start_month, 'YYYY-MM'),
+		        b.rollover, b.created_at, b.updated_at
+		 FROM budgets b
+		 JOIN categories c ON c.id = b.category_id
+		 WHERE b.id = $1 AND b.owner_id = $2 AND c.deleted_at IS NULL`, id, ownerID,
+	).Scan(&b.ID, &b.CategoryID, &b.CategoryName, &b.IncludeSubcategories, &b.Amount, &b.Currency,
+		&b.StartMonth, &b.Rollover, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *BudgetRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateBudgetRequest) (*domain.Budget, error) {
+	if req.Amount != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE budgets SET amount = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Amount, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.StartMonth != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE budgets SET start_month = to_date($1, 'YYYY-MM'), updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.StartMonth, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Rollover != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE budgets SET rollover = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Rollover, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.IncludeSubcategories != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE budgets SET include_subcategories = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.IncludeSubcategories, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *BudgetRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM budgets WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// MonthlySpending returns the owner's completed expenses booked against each
+// of their budgets' categories, and their subcategories for budgets that
+// include them, leaving out deleted categories, per month (YYYY-MM), from the budget's start month up to and
+// including the given month. Split transactions count the lines booked
+// against those categories.
+func (r *BudgetRepository) MonthlySpending(ctx context.Context, ownerID string, month time.Time) (map[string]map[string]decimal.Decimal, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH RECURSIVE budget_categories AS (
+		     SELECT b.id AS budget_id, b.category_id, b.include_subcategories
+		     FROM budgets b
+		     JOIN categories c ON c.id = b.category_id
+		     WHERE b.owner_id = $2 AND c.deleted_at IS NULL
+		     UNION
+		     SELECT bc.budget_id, c.id, bc.include_subcategories
+		     FROM budget_categories bc
+		     JOIN categories c ON c.parent_id = bc.category_id
+		     WHERE bc.include_subcategories AND c.deleted_at IS NULL
+		 )
+		 SELECT b.id, to_char(t.date, 'YYYY-MM') AS month, SUM(COALESCE(s.amount, t.amount))
+		 FROM budgets b
+		 JOIN budget_categories bc ON bc.budget_id = b.id
+		 JOIN (transactions t LEFT JOIN transaction_splits s ON s.transaction_id = t.id)
+		      ON COALESCE(s.category_id, t.category_id) = bc.category_id
+		      AND t.owner_id = b.owner_id
+		      AND t.currency = b.currency
+		      AND t.type = 'expense'
+		      AND t.status = 'completed'
+		      AND t.deleted_at IS NULL
+		      AND t.date >= b.start_month
+		      AND t.date < ($1::date + INTERVAL '1 month')
+		 WHERE b.owner_id = $2
+		 GROUP BY b.id, month`,
+		month, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spending := make(map[string]map[string]decimal.Decimal)
+	for rows.Next() {
+		var budgetID, m string
+		var spent decimal.Decimal
+		if err := rows.Scan(&budgetID, &m, &spent); err != nil {
+			return nil, err
+		}
+		if spending[budgetID] == nil {
+			spending[budgetID] = make(map[string]decimal.Decimal)
+		}
+		spending[budgetID][m] = spent
+	}
+	return spending, rows.Err()
+}
@@ -2,12 +2,33 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"personal-finance-backend/internal/domain"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Categories are read from the owner's own categories plus the shared ones
+// (owner_id IS NULL); only the owner's own categories can be changed.
+// Soft-deleted categories are left out unless asked for.
+const categoryColumns = `id, name, type, parent_id, owner_id IS NULL, created_at, updated_at, deleted_at`
+
+func scanCategory(row pgx.Row, c *domain.Category) error {
+	return row.Scan(&c.ID, &c.Name, &c.Type, &c.ParentID, &c.Shared, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt)
+}
+
+// categorySubtree selects the ID of the category whose ID is the given
+// expression and the IDs of all its descendants.
+func categorySubtree(id string) string {
+	return `WITH RECURSIVE subtree AS (
+		    SELECT id FROM categories WHERE id = ` + id + `
+		    UNION
+		    SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+		) SELECT id FROM subtree`
+}
+
 type CategoryRepository struct {
 	db *pgxpool.Pool
 }
@@ -16,23 +37,25 @@ func NewCategoryRepository(db *pgxpool.Pool) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-func (r *CategoryRepository) Create(ctx context.Context, req domain.CreateCategoryRequest) (*domain.Category, error) {
+func (r *CategoryRepository) Create(ctx context.Context, ownerID string, req domain.CreateCategoryRequest) (*domain.Category, error) {
 	var c domain.Category
-	err := r.db.QueryRow(ctx,
-		`INSERT INTO categories (name, type) VALUES ($1, $2)
-		 RETURNING id, name, type, created_at, updated_at`,
-		req.Name, req.Type,
-	).Scan(&c.ID, &c.Name, &c.Type, &c.CreatedAt, &c.UpdatedAt)
+	err := scanCategory(r.db.QueryRow(ctx,
+		`INSERT INTO categories (name, type, parent_id, owner_id) VALUES ($1, $2, $3, $4)
+		 RETURNING `+categoryColumns,
+		req.Name, req.Type, req.ParentID, ownerID,
+	), &c)
 	if err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
-func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
+func (r *CategoryRepository) GetAll(ctx context.Context, ownerID string, filter domain.CategoryFilter) ([]domain.Category, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT id, name, type, created_at, updated_at
-		 FROM categories ORDER BY type, name`,
+		`SELECT `+categoryColumns+`
+		 FROM categories
+		 WHERE (owner_id = $1 OR owner_id IS NULL) AND ($2 OR deleted_at IS NULL)
+		 ORDER BY type, name`, ownerID, filter.IncludeDeleted,
 	)
 	if err != nil {
 		return nil, err
@@ -42,7 +65,7 @@ func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, err
 	var categories []domain.Category
 	for rows.Next() {
 		var c domain.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.Type, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err := scanCategory(rows, &c); err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
@@ -50,33 +73,206 @@ func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, err
 	return categories, nil
 }
 
-func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+func (r *CategoryRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Category, error) {
 	var c domain.Category
-	err := r.db.QueryRow(ctx,
-		`SELECT id, name, type, created_at, updated_at
-		 FROM categories WHERE id = $1`, id,
-	).Scan(&c.ID, &c.Name, &c.Type, &c.CreatedAt, &c.UpdatedAt)
+	err := scanCategory(r.db.QueryRow(ctx,
+		`SELECT `+categoryColumns+`
+		 FROM categories WHERE id = $1 AND (owner_id = $2 OR owner_id IS NULL) AND deleted_at IS NULL`, id, ownerID,
+	), &c)
 	if err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
-func (r *CategoryRepository) Update(ctx context.Context, id string, req domain.UpdateCategoryRequest) (*domain.Category, error) {
+// Update changes one of the owner's own categories. Shared categories are
+// left untouched and read back unchanged.
+func (r *CategoryRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateCategoryRequest) (*domain.Category, error) {
 	if req.Name != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE categories SET name = $1, updated_at = now() WHERE id = $2`, *req.Name, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE categories SET name = $1, updated_at = now() WHERE id = $2 AND owner_id = $3 AND deleted_at IS NULL`, *req.Name, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.Type != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE categories SET type = $1, updated_at = now() WHERE id = $2`, *req.Type, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE categories SET type = $1, updated_at = now() WHERE id = $2 AND owner_id = $3 AND deleted_at IS NULL`, *req.Type, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
-	return r.GetByID(ctx, id)
+	if req.ParentID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE categories SET parent_id = NULLIF($1, '')::uuid, updated_at = now() WHERE id = $2 AND owner_id = $3 AND deleted_at IS NULL`, *req.ParentID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Delete soft-deletes one of the owner's categories. Its transactions keep
+// pointing at it and still show its name.
+func (r *CategoryRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE categories SET deleted_at = now() WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Usage counts the owner's transactions, split or not, recurring rules,
+// subcategories, budgets, payees and categorization rules that use a
+// category.
+func (r *CategoryRepository) Usage(ctx context.Context, ownerID, id string) (domain.CategoryUsage, error) {
+	var u domain.CategoryUsage
+	err := r.db.QueryRow(ctx,
+		`SELECT (SELECT COUNT(*) FROM transactions t
+		         WHERE t.owner_id = $2 AND t.deleted_at IS NULL
+		           AND (t.category_id = $1 OR EXISTS (
+		               SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id AND s.category_id = $1))),
+		        (SELECT COUNT(*) FROM recurring_transactions WHERE category_id = $1 AND owner_id = $2),
+		        (SELECT COUNT(*) FROM categories WHERE parent_id = $1 AND owner_id = $2 AND deleted_at IS NULL),
+		        (SELECT COUNT(*) FROM budgets WHERE category_id = $1 AND owner_id = $2),
+		        (SELECT COUNT(*) FROM payees WHERE default_category_id = $1 AND owner_id = $2),
+		        (SELECT COUNT(*) FROM rules WHERE set_category_id = $1 AND owner_id = $2)`, id, ownerID,
+	).Scan(&u.Transactions, &u.RecurringTransactions, &u.Subcategories, &u.Budgets, &u.Payees, &u.Rules)
+	return u, err
+}
+
+// IsInSubtree reports whether candidateID is the category id or one of its
+// descendants.
+func (r *CategoryRepository) IsInSubtree(ctx context.Context, id, candidateID string) (bool, error) {
+	var found bool
+	err := r.db.QueryRow(ctx,
+		`SELECT $2::uuid IN (`+categorySubtree("$1")+`)`, id, candidateID,
+	).Scan(&found)
+	return found, err
+}
+
+// Merge moves the transactions and split lines (deleted ones included),
+// recurring rules, subcategories, payee defaults, categorization rules and
+// budget of one of the owner's categories to targetID and soft-deletes it,
+// all in a single database transaction. The budget stays behind when the
+// target already has one. Returns what was moved.
+func (r *CategoryRepository) Merge(ctx context.Context, ownerID, id, targetID string) (domain.CategoryUsage, error) {
+	var moved domain.CategoryUsage
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return moved, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Deleting first locks the category against concurrent changes
+	tag, err := tx.Exec(ctx,
+		`UPDATE categories SET deleted_at = now() WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL`, id, ownerID)
+	if err != nil {
+		return moved, err
+	}
+	if tag.RowsAffected() == 0 {
+		return moved, pgx.ErrNoRows
+	}
+
+	// Split lines go first so that transactions are counted once: those
+	// whose own category moves too are counted below
+	var splitOnly int
+	err = tx.QueryRow(ctx,
+		`WITH moved AS (
+		     UPDATE transaction_splits s SET category_id = $2
+		     FROM transactions t
+		     WHERE t.id = s.transaction_id AND s.category_id = $1 AND t.owner_id = $3
+		     RETURNING t.id, t.category_id, t.deleted_at
+		 )
+		 SELECT COUNT(DISTINCT id) FILTER (WHERE deleted_at IS NULL AND category_id <> $1) FROM moved`, id, targetID, ownerID,
+	).Scan(&splitOnly)
+	if err != nil {
+		return moved, err
+	}
+
+	err = tx.QueryRow(ctx,
+		`WITH moved AS (
+		     UPDATE transactions SET category_id = $2, updated_at = now()
+		     WHERE category_id = $1 AND owner_id = $3
+		     RETURNING deleted_at
+		 )
+		 SELECT COUNT(*) FILTER (WHERE deleted_at IS NULL) FROM moved`, id, targetID, ownerID,
+	).Scan(&moved.Transactions)
+	if err != nil {
+		return moved, err
+	}
+	moved.Transactions += splitOnly
+
+	tag, err = tx.Exec(ctx,
+		`UPDATE recurring_transactions SET category_id = $2, updated_at = now()
+		 WHERE category_id = $1 AND owner_id = $3`, id, targetID, ownerID)
+	if err != nil {
+		return moved, err
+	}
+	moved.RecurringTransactions = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx,
+		`UPDATE categories SET parent_id = $2, updated_at = now()
+		 WHERE parent_id = $1 AND owner_id = $3 AND deleted_at IS NULL`, id, targetID, ownerID)
+	if err != nil {
+		return moved, err
+	}
+	moved.Subcategories = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx,
+		`UPDATE payees SET default_category_id = $2, updated_at = now()
+		 WHERE default_category_id = $1 AND owner_id = $3`, id, targetID, ownerID)
+	if err != nil {
+		return moved, err
+	}
+	moved.Payees = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx,
+		`UPDATE rules SET set_category_id = $2, updated_at = now()
+		 WHERE set_category_id = $1 AND owner_id = $3`, id, targetID, ownerID)
+	if err != nil {
+		return moved, err
+	}
+	moved.Rules = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx,
+		`UPDATE budgets SET category_id = $2, updated_at = now()
+		 WHERE category_id = $1 AND owner_id = $3
+		   AND NOT EXISTS (SELECT 1 FROM budgets WHERE category_id = $2 AND owner_id = $3)`, id, targetID, ownerID)
+	if err != nil {
+		return moved, err
+	}
+	moved.Budgets = int(tag.RowsAffected())
+
+	return moved, tx.Commit(ctx)
 }
 
-func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
-	return err
+// Restore brings back a soft-deleted category. Fails with a unique
+// violation when another category has taken its name in the meantime.
+func (r *CategoryRepository) Restore(ctx context.Context, ownerID, id string) (*domain.Category, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE categories SET deleted_at = NULL, updated_at = now()
+		 WHERE id = $1 AND owner_id = $2 AND deleted_at IS NOT NULL`, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Purge permanently removes categories deleted before cutoff that no
+// transaction, split line or recurring rule refers to anymore. Their budgets go with
+// them.
+func (r *CategoryRepository) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM categories c
+		 WHERE c.deleted_at < $1
+		   AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.category_id = c.id)
+		   AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.category_id = c.id)
+		   AND NOT EXISTS (SELECT 1 FROM recurring_transactions r WHERE r.category_id = c.id)`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
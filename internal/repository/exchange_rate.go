@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const exchangeRateColumns = `id, date::text, from_currency, to_currency, rate, created_at, updated_at`
+
+func scanExchangeRate(row pgx.Row, e *domain.ExchangeRate) error {
+	return row.Scan(&e.ID, &e.Date, &e.FromCurrency, &e.ToCurrency, &e.Rate, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// fxRateJoin joins fx.rate, the factor that converts a transaction's amount
+// into the currency bound to param. It uses the latest rate dated on or
+// before the transaction among the rates of the transaction's owner, falling
+// back to the inverse of the opposite pair. The rate is 1 for transactions already in that currency and NULL when no
+// rate is known.
+func fxRateJoin(param string) string {
+	return fmt.Sprintf(`
+	LEFT JOIN LATERAL (
+		SELECT CASE WHEN t.currency = %[1]s THEN 1 ELSE (
+			SELECT r.rate FROM (
+				SELECT er.date, er.rate FROM exchange_rates er
+				WHERE er.owner_id = t.owner_id AND er.from_currency = t.currency AND er.to_currency = %[1]s
+				  AND er.date <= t.date
+				UNION ALL
+				SELECT er.date, 1 / er.rate FROM exchange_rates er
+				WHERE er.owner_id = t.owner_id AND er.from_currency = %[1]s AND er.to_currency = t.currency
+				  AND er.date <= t.date
+			) r
+			ORDER BY r.date DESC
+			LIMIT 1
+		) END AS rate
+	) fx ON true`, param)
+}
+
+type ExchangeRateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExchangeRateRepository(db *pgxpool.Pool) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+func (r *ExchangeRateRepository) Create(ctx context.Context, ownerID string, req domain.CreateExchangeRateRequest) (*domain.ExchangeRate, error) {
+	var e domain.ExchangeRate
+	err := scanExchangeRate(r.db.QueryRow(ctx,
+		`INSERT INTO exchange_rates (date, from_currency, to_currency, rate, owner_id)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+exchangeRateColumns,
+		req.Date, req.FromCurrency, req.ToCurrency, req.Rate, ownerID,
+	), &e)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Upsert stores all rates in a single database transaction. A rate for a
+// pair and date that already exists is overwritten, so the same file can be
+// uploaded again safely. Returns the number of rates stored.
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, ownerID string, reqs []domain.CreateExchangeRateRequest) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, req := range reqs {
+		batch.Queue(
+			`INSERT INTO exchange_rates (date, from_currency, to_currency, rate, owner_id)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (owner_id, from_currency, to_currency, date)
+			 DO UPDATE SET rate = EXCLUDED.rate, updated_at = now()`,
+			req.Date, req.FromCurrency, req.ToCurrency, req.Rate, ownerID,
+		)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(reqs), nil
+}
+
+func (r *ExchangeRateRepository) GetAll(ctx context.Context, ownerID string, filter domain.ExchangeRateFilter) ([]domain.ExchangeRate, int, error) {
+	// Set defaults
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	offset := (filter.Page - 1) * filter.Limit
+
+	conditions := []string{"owner_id = $1"}
+	args := []interface{}{ownerID}
+	argIdx := 2
+
+	if filter.FromCurrency != "" {
+		conditions = append(conditions, fmt.Sprintf("from_currency = $%d", argIdx))
+		args = append(args, filter.FromCurrency)
+		argIdx++
+	}
+	if filter.ToCurrency != "" {
+		conditions = append(conditions, fmt.Sprintf("to_currency = $%d", argIdx))
+		args = append(args, filter.ToCurrency)
+		argIdx++
+	}
+	if filter.DateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("date >= $%d", argIdx))
+		args = append(args, filter.DateFrom)
+		argIdx++
+	}
+	if filter.DateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("date <= $%d", argIdx))
+		args = append(args, filter.DateTo)
+		argIdx++
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM exchange_rates %s`, whereClause)
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM exchange_rates
+		%s
+		ORDER BY date DESC, from_currency, to_currency
+		LIMIT $%d OFFSET $%d`,
+		exchangeRateColumns, whereClause, argIdx, argIdx+1,
+	)
+	args = append(args, filter.Limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	rates := []domain.ExchangeRate{}
+	for rows.Next() {
+		var e domain.ExchangeRate
+		if err := scanExchangeRate(rows, &e); err != nil {
+			return nil, 0, err
+		}
+		rates = append(rates, e)
+	}
+	return rates, total, rows.Err()
+}
+
+func (r *ExchangeRateRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.ExchangeRate, error) {
+	var e domain.ExchangeRate
+	err := scanExchangeRate(r.db.QueryRow(ctx,
+		`SELECT `+exchangeRateColumns+` FROM exchange_rates WHERE id = $1 AND owner_id = $2`, id, ownerID,
+	), &e)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ExchangeRateRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateExchangeRateRequest) (*domain.ExchangeRate, error) {
+	if req.Date != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE exchange_rates SET date = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Date, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Rate != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE exchange_rates SET rate = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Rate, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *ExchangeRateRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM exchange_rates WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
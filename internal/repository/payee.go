@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// payeeSelect joins the default category's name and counts the live
+// transactions with each payee.
+const payeeSelect = `
+	SELECT p.id, p.name, p.default_category_id, c.name,
+	       (SELECT COUNT(*) FROM transactions t WHERE t.payee_id = p.id AND t.deleted_at IS NULL),
+	       p.created_at, p.updated_at
+	FROM payees p
+	LEFT JOIN categories c ON c.id = p.default_category_id`
+
+func scanPayee(row pgx.Row, p *domain.Payee) error {
+	return row.Scan(&p.ID, &p.Name, &p.DefaultCategoryID, &p.DefaultCategoryName, &p.Transactions, &p.CreatedAt, &p.UpdatedAt)
+}
+
+type PayeeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPayeeRepository(db *pgxpool.Pool) *PayeeRepository {
+	return &PayeeRepository{db: db}
+}
+
+func (r *PayeeRepository) Create(ctx context.Context, ownerID string, req domain.CreatePayeeRequest) (*domain.Payee, error) {
+	var id string
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO payees (owner_id, name, default_category_id) VALUES ($1, $2, $3) RETURNING id`,
+		ownerID, req.Name, req.DefaultCategoryID,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *PayeeRepository) GetAll(ctx context.Context, ownerID string) ([]domain.Payee, error) {
+	rows, err := r.db.Query(ctx, payeeSelect+`
+		WHERE p.owner_id = $1
+		ORDER BY lower(p.name)`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payees := []domain.Payee{}
+	for rows.Next() {
+		var p domain.Payee
+		if err := scanPayee(rows, &p); err != nil {
+			return nil, err
+		}
+		payees = append(payees, p)
+	}
+	return payees, rows.Err()
+}
+
+func (r *PayeeRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Payee, error) {
+	var p domain.Payee
+	err := scanPayee(r.db.QueryRow(ctx, payeeSelect+`
+		WHERE p.id = $1 AND p.owner_id = $2`, id, ownerID,
+	), &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PayeeRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdatePayeeRequest) (*domain.Payee, error) {
+	if req.Name != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE payees SET name = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Name, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.DefaultCategoryID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE payees SET default_category_id = NULLIF($1, '')::uuid, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.DefaultCategoryID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Delete removes a payee. Its transactions are kept without a payee.
+func (r *PayeeRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM payees WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
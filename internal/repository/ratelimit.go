@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RateLimitRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRateLimitRepository(db *pgxpool.Pool) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// refilledTokens is the content of bucket b once refilled at $2 tokens per
+// minute since its last update, capped at $2.
+const refilledTokens = `LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $2::float8 / 60)`
+
+// Take refills the token bucket of key at limit tokens per minute, up to
+// limit, and takes one token if there is one. Returns the tokens left and
+// whether a token was taken. The row lock of the upsert makes concurrent
+// requests from several machines queue up on the same bucket.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit int) (float64, bool, error) {
+	var tokens float64
+	var allowed bool
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+		 VALUES ($1, $2::float8 - 1, true, now())
+		 ON CONFLICT (key) DO UPDATE SET
+		     allowed = `+refilledTokens+` >= 1,
+		     tokens = CASE WHEN `+refilledTokens+` >= 1 THEN `+refilledTokens+` - 1 ELSE `+refilledTokens+` END,
+		     updated_at = now()
+		 RETURNING tokens, allowed`,
+		key, float64(limit),
+	).Scan(&tokens, &allowed)
+	return tokens, allowed, err
+}
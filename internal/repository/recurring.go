@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const recurringSelect = `
+	SELECT r.id, r.type, r.category_id, c.name, r.account_id, r.amount, r.currency, r.description,
+	       r.frequency, r.day_of_month, r.start_date::text, r.end_date::text, r.max_occurrences,
+	       r.occurrences, r.last_date::text, r.next_date::text, r.is_active, r.created_at, r.updated_at
+	FROM recurring_transactions r
+	JOIN categories c ON c.id = r.category_id`
+
+func scanRecurring(row pgx.Row, rt *domain.RecurringTransaction) error {
+	return row.Scan(
+		&rt.ID, &rt.Type, &rt.CategoryID, &rt.CategoryName, &rt.AccountID, &rt.Amount, &rt.Currency, &rt.Description,
+		&rt.Frequency, &rt.DayOfMonth, &rt.StartDate, &rt.EndDate, &rt.MaxOccurrences,
+		&rt.Occurrences, &rt.LastDate, &rt.NextDate, &rt.IsActive, &rt.CreatedAt, &rt.UpdatedAt,
+	)
+}
+
+type RecurringRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecurringRepository(db *pgxpool.Pool) *RecurringRepository {
+	return &RecurringRepository{db: db}
+}
+
+// Create inserts a rule. nextDate is its first occurrence, or nil when the
+// schedule has no occurrences at all.
+func (r *RecurringRepository) Create(ctx context.Context, ownerID string, req domain.CreateRecurringTransactionRequest, nextDate *string) (*domain.RecurringTransaction, error) {
+	var id string
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO recurring_transactions (type, category_id, account_id, amount, currency, description,
+		     frequency, day_of_month, start_date, end_date, max_occurrences, next_date, owner_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		 RETURNING id`,
+		req.Type, req.CategoryID, req.AccountID, req.Amount, req.Currency, req.Description,
+		req.Frequency, req.DayOfMonth, req.StartDate, req.EndDate, req.MaxOccurrences, nextDate, ownerID,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *RecurringRepository) GetAll(ctx context.Context, ownerID string) ([]domain.RecurringTransaction, error) {
+	rows, err := r.db.Query(ctx, recurringSelect+`
+		WHERE r.owner_id = $1
+		ORDER BY r.next_date NULLS LAST, r.created_at`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.RecurringTransaction
+	for rows.Next() {
+		var rt domain.RecurringTransaction
+		if err := scanRecurring(rows, &rt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rt)
+	}
+	return rules, nil
+}
+
+func (r *RecurringRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.RecurringTransaction, error) {
+	var rt domain.RecurringTransaction
+	if err := scanRecurring(r.db.QueryRow(ctx, recurringSelect+`
+		WHERE r.id = $1 AND r.owner_id = $2`, id, ownerID,
+	), &rt); err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+// Update applies the changes and stores the recomputed next occurrence.
+func (r *RecurringRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateRecurringTransactionRequest, nextDate *string) (*domain.RecurringTransaction, error) {
+	if req.CategoryID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET category_id = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.CategoryID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.AccountID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET account_id = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.AccountID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Amount != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET amount = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Amount, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET description = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Description, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Frequency != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET frequency = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Frequency, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.DayOfMonth != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET day_of_month = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.DayOfMonth, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndDate != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET end_date = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.EndDate, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.MaxOccurrences != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET max_occurrences = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.MaxOccurrences, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET is_active = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.IsActive, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.db.Exec(ctx, `UPDATE recurring_transactions SET next_date = $1 WHERE id = $2 AND owner_id = $3`, nextDate, id, ownerID); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *RecurringRepository) Delete(ctx context.Context, ownerID, id string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM recurring_transactions WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	return err
+}
+
+// MaterializeDue generates the transactions of every active rule that is due
+// on or before today. Each rule is locked and processed in its own database
+// transaction: plan returns the occurrence dates to insert and the rule's new
+// next date, which must lie after today. A rule that plan fails for is
+// deactivated so it doesn't hold up the rules due after it. Rows locked by
+// another instance are skipped, and the unique (recurring_id,
+// recurring_date) index turns any repeated occurrence into a no-op, so
+// running this concurrently or after a crash never creates duplicates. Returns the number of transactions created.
+func (r *RecurringRepository) MaterializeDue(ctx context.Context, today time.Time,
+	plan func(domain.RecurringTransaction) (dates []string, nextDate *string, err error)) (int, error) {
+	created := 0
+	for {
+		n, done, err := r.materializeNext(ctx, today, plan)
+		if err != nil {
+			return created, err
+		}
+		created += n
+		if done {
+			return created, nil
+		}
+	}
+}
+
+func (r *RecurringRepository) materializeNext(ctx context.Context, today time.Time,
+	plan func(domain.RecurringTransaction) ([]string, *string, error)) (int, bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var rt domain.RecurringTransaction
+	err = scanRecurring(tx.QueryRow(ctx, recurringSelect+`
+		WHERE r.is_active = true AND r.next_date <= $1
+		ORDER BY r.next_date
+		LIMIT 1
+		FOR UPDATE OF r SKIP LOCKED`, today,
+	), &rt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, true, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	dates, nextDate, err := plan(rt)
+	if err != nil {
+		if _, err := tx.Exec(ctx,
+			`UPDATE recurring_transactions SET is_active = false, updated_at = now() WHERE id = $1`, rt.ID,
+		); err != nil {
+			return 0, false, err
+		}
+		return 0, false, tx.Commit(ctx)
+	}
+
+	created := 0
+	for _, date := range dates {
+		tag, err := tx.Exec(ctx,
+			`INSERT INTO transactions (type, category_id, account_id, amount, currency, description, status, date,
+			     recurring_id, recurring_date, owner_id)
+			 VALUES ($1, $2, $3, $4, $5, $6, 'completed', $7, $8, $7,
+			     (SELECT owner_id FROM recurring_transactions WHERE id = $8))
+			 ON CONFLICT (recurring_id, recurring_date) WHERE recurring_id IS NOT NULL DO NOTHING`,
+			rt.Type, rt.CategoryID, rt.AccountID, rt.Amount, rt.Currency, rt.Description, date, rt.ID,
+		)
+		if err != nil {
+			return 0, false, err
+		}
+		created += int(tag.RowsAffected())
+	}
+
+	if len(dates) > 0 {
+		rt.LastDate = &dates[len(dates)-1]
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE recurring_transactions
+		 SET occurrences = occurrences + $1, last_date = $2, next_date = $3, updated_at = now()
+		 WHERE id = $4`,
+		len(dates), rt.LastDate, nextDate, rt.ID,
+	); err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, err
+	}
+	return created, false, nil
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReportRepository aggregates transactions in SQL. Every report accepts the
+// same filter as transaction listing; pagination fields are ignored.
+// Transfers are never counted as income or expense. With ConvertTo set,
+// every amount is converted into that currency and reported as one group.
+// Split transactions are read once per line, so categories get the amounts
+// of their lines.
+type ReportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepository(db *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+func (r *ReportRepository) Summary(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.SummaryReport, error) {
+	q := newReportQuery(ownerID, filter)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT %[1]s AS currency,
+		       COALESCE(SUM(%[2]s) FILTER (WHERE t.type = 'income'), 0) AS income,
+		       COALESCE(SUM(%[2]s) FILTER (WHERE t.type = 'expense'), 0) AS expense,
+		       COUNT(DISTINCT t.id)
+		`+reportFrom+`
+		%[3]s
+		GROUP BY 1
+		ORDER BY 1`, q.currency, q.amount, q.joinsAndWhere), q.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []domain.SummaryReport{}
+	for rows.Next() {
+		var s domain.SummaryReport
+		if err := rows.Scan(&s.Currency, &s.Income, &s.Expense, &s.Count); err != nil {
+			return nil, err
+		}
+		s.Net = s.Income.Sub(s.Expense)
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+func (r *ReportRepository) ByCategory(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.CategoryReport, error) {
+	q := newReportQuery(ownerID, filter)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT c.id, c.name, t.type, %[1]s AS currency, SUM(%[2]s) AS total, COUNT(*)
+		`+reportFrom+`
+		JOIN categories c ON c.id = COALESCE(s.category_id, t.category_id)
+		%[3]s
+		GROUP BY 1, 2, 3, 4
+		ORDER BY t.type, total DESC`, q.currency, q.amount, q.joinsAndWhere), q.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.CategoryReport{}
+	for rows.Next() {
+		var c domain.CategoryReport
+		if err := rows.Scan(&c.CategoryID, &c.CategoryName, &c.Type, &c.Currency, &c.Total, &c.Count); err != nil {
+			return nil, err
+		}
+		reports = append(reports, c)
+	}
+	return reports, rows.Err()
+}
+
+// ByTag totals transactions per tag. A transaction with several tags counts
+// towards each of them, so the totals can add up to more than the summary.
+func (r *ReportRepository) ByTag(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.TagReport, error) {
+	q := newReportQuery(ownerID, filter)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT g.id, g.name, t.type, %[1]s AS currency, SUM(%[2]s) AS total, COUNT(DISTINCT t.id)
+		`+reportFrom+`
+		JOIN transaction_tags tg ON tg.transaction_id = t.id
+		JOIN tags g ON g.id = tg.tag_id
+		%[3]s
+		GROUP BY 1, 2, 3, 4
+		ORDER BY t.type, total DESC`, q.currency, q.amount, q.joinsAndWhere), q.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.TagReport{}
+	for rows.Next() {
+		var g domain.TagReport
+		if err := rows.Scan(&g.TagID, &g.TagName, &g.Type, &g.Currency, &g.Total, &g.Count); err != nil {
+			return nil, err
+		}
+		reports = append(reports, g)
+	}
+	return reports, rows.Err()
+}
+
+// ByPayee totals transactions per payee. Transactions without a payee are
+// left out.
+func (r *ReportRepository) ByPayee(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.PayeeReport, error) {
+	q := newReportQuery(ownerID, filter)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT p.id, p.name, t.type, %[1]s AS currency, SUM(%[2]s) AS total, COUNT(DISTINCT t.id)
+		`+reportFrom+`
+		JOIN payees p ON p.id = t.payee_id
+		%[3]s
+		GROUP BY 1, 2, 3, 4
+		ORDER BY t.type, total DESC`, q.currency, q.amount, q.joinsAndWhere), q.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.PayeeReport{}
+	for rows.Next() {
+		var p domain.PayeeReport
+		if err := rows.Scan(&p.PayeeID, &p.PayeeName, &p.Type, &p.Currency, &p.Total, &p.Count); err != nil {
+			return nil, err
+		}
+		reports = append(reports, p)
+	}
+	return reports, rows.Err()
+}
+
+func (r *ReportRepository) MonthlyTrend(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.MonthlyReport, error) {
+	q := newReportQuery(ownerID, filter)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT to_char(date_trunc('month', t.date), 'YYYY-MM') AS month, %[1]s AS currency,
+		       COALESCE(SUM(%[2]s) FILTER (WHERE t.type = 'income'), 0) AS income,
+		       COALESCE(SUM(%[2]s) FILTER (WHERE t.type = 'expense'), 0) AS expense
+		`+reportFrom+`
+		%[3]s
+		GROUP BY 1, 2
+		ORDER BY 1, 2`, q.currency, q.amount, q.joinsAndWhere), q.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.MonthlyReport{}
+	for rows.Next() {
+		var m domain.MonthlyReport
+		if err := rows.Scan(&m.Month, &m.Currency, &m.Income, &m.Expense); err != nil {
+			return nil, err
+		}
+		m.Net = m.Income.Sub(m.Expense)
+		reports = append(reports, m)
+	}
+	return reports, rows.Err()
+}
+
+// MissingRates counts the transactions a converted report would include
+// that have no exchange rate into filter.ConvertTo on their date.
+func (r *ReportRepository) MissingRates(ctx context.Context, ownerID string, filter domain.TransactionFilter) (int, error) {
+	q := newReportQuery(ownerID, filter)
+
+	var count int
+	err := r.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COUNT(DISTINCT t.id)
+		`+reportFrom+`
+		%s AND fx.rate IS NULL`, q.joinsAndWhere), q.args...,
+	).Scan(&count)
+	return count, err
+}
+
+// reportFrom reads every transaction once per split line, joined as s, or
+// once with s NULL when it isn't split.
+const reportFrom = `FROM transactions t
+		LEFT JOIN transaction_splits s ON s.transaction_id = t.id`
+
+// reportQuery holds the parts of a report query that depend on the filter:
+// the currency and amount expressions to aggregate, and the joins and WHERE
+// clause that follow reportFrom.
+type reportQuery struct {
+	currency      string
+	amount        string
+	joinsAndWhere string
+	args          []interface{}
+}
+
+func newReportQuery(ownerID string, filter domain.TransactionFilter) reportQuery {
+	whereClause, args := reportWhere(ownerID, filter)
+	if filter.ConvertTo == "" {
+		return reportQuery{currency: "t.currency", amount: "COALESCE(s.amount, t.amount)", joinsAndWhere: whereClause, args: args}
+	}
+
+	args = append(args, filter.ConvertTo)
+	param := fmt.Sprintf("$%d", len(args))
+	return reportQuery{
+		currency:      param + "::varchar",
+		amount:        "COALESCE(s.amount, t.amount) * fx.rate",
+		joinsAndWhere: fxRateJoin(param) + "\n\t\t" + whereClause,
+		args:          args,
+	}
+}
+
+// reportWhere is transactionWhere with transfers and deleted transactions
+// left out.
+func reportWhere(ownerID string, filter domain.TransactionFilter) (string, []interface{}) {
+	filter.IncludeDeleted = false
+	whereClause, args := filterWhere(ownerID, filter, true)
+	return whereClause + " AND t.type <> 'transfer'", args
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const ruleColumns = `r.id, r.name, r.priority, r.enabled,
+	       r.description_contains, r.description_regex, r.amount_min, r.amount_max, r.type,
+	       r.set_category_id, r.set_payee_id, r.add_tags, r.set_status::text,
+	       r.created_at, r.updated_at`
+
+func scanRule(row pgx.Row, rule *domain.Rule) error {
+	c, a := &rule.Conditions, &rule.Actions
+	return row.Scan(&rule.ID, &rule.Name, &rule.Priority, &rule.Enabled,
+		&c.DescriptionContains, &c.DescriptionRegex, &c.AmountMin, &c.AmountMax, &c.Type,
+		&a.CategoryID, &a.PayeeID, &a.Tags, &a.Status,
+		&rule.CreatedAt, &rule.UpdatedAt)
+}
+
+type RuleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRuleRepository(db *pgxpool.Pool) *RuleRepository {
+	return &RuleRepository{db: db}
+}
+
+func (r *RuleRepository) Create(ctx context.Context, ownerID string, req domain.CreateRuleRequest) (*domain.Rule, error) {
+	c, a := req.Conditions, req.Actions
+	var id string
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO rules (owner_id, name, priority, enabled,
+		                    description_contains, description_regex, amount_min, amount_max, type,
+		                    set_category_id, set_payee_id, add_tags, set_status)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		 RETURNING id`,
+		ownerID, req.Name, req.Priority, req.Enabled == nil || *req.Enabled,
+		c.DescriptionContains, c.DescriptionRegex, c.AmountMin, c.AmountMax, c.Type,
+		a.CategoryID, a.PayeeID, domain.NormalizeTags(a.Tags), a.Status,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// GetAll lists the owner's rules in the order they run.
+func (r *RuleRepository) GetAll(ctx context.Context, ownerID string) ([]domain.Rule, error) {
+	return r.list(ctx, `SELECT `+ruleColumns+` FROM rules r
+		WHERE r.owner_id = $1
+		ORDER BY r.priority, r.created_at`, ownerID)
+}
+
+// GetEnabled lists the owner's enabled rules in the order they run. Rules
+// that set a deleted category are left out.
+func (r *RuleRepository) GetEnabled(ctx context.Context, ownerID string) ([]domain.Rule, error) {
+	return r.list(ctx, `SELECT `+ruleColumns+` FROM rules r
+		LEFT JOIN categories c ON c.id = r.set_category_id
+		WHERE r.owner_id = $1 AND r.enabled AND c.deleted_at IS NULL
+		ORDER BY r.priority, r.created_at`, ownerID)
+}
+
+func (r *RuleRepository) list(ctx context.Context, query string, args ...any) ([]domain.Rule, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []domain.Rule{}
+	for rows.Next() {
+		var rule domain.Rule
+		if err := scanRule(rows, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *RuleRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Rule, error) {
+	var rule domain.Rule
+	err := scanRule(r.db.QueryRow(ctx, `SELECT `+ruleColumns+` FROM rules r
+		WHERE r.id = $1 AND r.owner_id = $2`, id, ownerID,
+	), &rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *RuleRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateRuleRequest) (*domain.Rule, error) {
+	if req.Name != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE rules SET name = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Name, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Priority != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE rules SET priority = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Priority, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Enabled != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE rules SET enabled = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Enabled, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if c := req.Conditions; c != nil {
+		if _, err := r.db.Exec(ctx,
+			`UPDATE rules SET description_contains = $1, description_regex = $2, amount_min = $3, amount_max = $4, type = $5,
+			     updated_at = now()
+			 WHERE id = $6 AND owner_id = $7`,
+			c.DescriptionContains, c.DescriptionRegex, c.AmountMin, c.AmountMax, c.Type, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if a := req.Actions; a != nil {
+		if _, err := r.db.Exec(ctx,
+			`UPDATE rules SET set_category_id = $1, set_payee_id = $2, add_tags = $3, set_status = $4, updated_at = now()
+			 WHERE id = $5 AND owner_id = $6`,
+			a.CategoryID, a.PayeeID, domain.NormalizeTags(a.Tags), a.Status, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *RuleRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM rules WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// tagSelect counts the live transactions carrying each tag.
+const tagSelect = `
+	SELECT g.id, g.name,
+	       (SELECT COUNT(*) FROM transaction_tags tt JOIN transactions t ON t.id = tt.transaction_id
+	        WHERE tt.tag_id = g.id AND t.deleted_at IS NULL),
+	       g.created_at, g.updated_at
+	FROM tags g`
+
+func scanTag(row pgx.Row, g *domain.Tag) error {
+	return row.Scan(&g.ID, &g.Name, &g.Transactions, &g.CreatedAt, &g.UpdatedAt)
+}
+
+type TagRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTagRepository(db *pgxpool.Pool) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) Create(ctx context.Context, ownerID string, req domain.CreateTagRequest) (*domain.Tag, error) {
+	var id string
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO tags (owner_id, name) VALUES ($1, $2) RETURNING id`, ownerID, req.Name,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+func (r *TagRepository) GetAll(ctx context.Context, ownerID string) ([]domain.Tag, error) {
+	rows, err := r.db.Query(ctx, tagSelect+`
+		WHERE g.owner_id = $1
+		ORDER BY g.name`, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+	for rows.Next() {
+		var g domain.Tag
+		if err := scanTag(rows, &g); err != nil {
+			return nil, err
+		}
+		tags = append(tags, g)
+	}
+	return tags, rows.Err()
+}
+
+func (r *TagRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Tag, error) {
+	var g domain.Tag
+	err := scanTag(r.db.QueryRow(ctx, tagSelect+`
+		WHERE g.id = $1 AND g.owner_id = $2`, id, ownerID,
+	), &g)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Update renames a tag; every transaction carrying it shows the new name.
+func (r *TagRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateTagRequest) (*domain.Tag, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE tags SET name = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, req.Name, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Delete removes a tag from every transaction carrying it.
+func (r *TagRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM tags WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
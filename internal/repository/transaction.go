@@ -4,12 +4,85 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"personal-finance-backend/internal/domain"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// transactionSelect is the column list and joins shared by every query that
+// returns full transactions. Rows are read back with scanTransaction.
+const transactionSelect = `
+	SELECT ` + transactionColumns + transactionFrom
+
+const transactionColumns = `t.id, t.type, t.category_id, COALESCE(c.name, ''), t.account_id, a.name, t.payee_id, p.name, t.amount, t.currency,
+	       t.description, t.status, t.date::text, t.transfer_id, t.transfer_direction, ` + transactionTags + `,
+	       t.created_at, t.updated_at, t.deleted_at, ` + transactionSplits
+
+// transactionSplits selects the split lines of transaction t as a JSON
+// array, NULL when it isn't split.
+const transactionSplits = `(SELECT json_agg(json_build_object('id', s.id, 'category_id', s.category_id, 'category_name', sc.name,
+	           'amount', s.amount, 'note', s.note) ORDER BY s.position)
+	       FROM transaction_splits s JOIN categories sc ON sc.id = s.category_id
+	       WHERE s.transaction_id = t.id)`
+
+// transactionTags selects the sorted tag names of transaction t.
+const transactionTags = `ARRAY(SELECT g.name FROM transaction_tags tt JOIN tags g ON g.id = tt.tag_id
+	       WHERE tt.transaction_id = t.id ORDER BY g.name)`
+
+// transactionHasTag is the condition that transaction t carries a tag whose
+// name satisfies cond, in terms of g.name.
+func transactionHasTag(cond string) string {
+	return `EXISTS (SELECT 1 FROM transaction_tags tt JOIN tags g ON g.id = tt.tag_id
+		WHERE tt.transaction_id = t.id AND ` + cond + `)`
+}
+
+const transactionFrom = `
+	FROM transactions t
+	LEFT JOIN categories c ON c.id = t.category_id
+	LEFT JOIN accounts a ON a.id = t.account_id
+	LEFT JOIN payees p ON p.id = t.payee_id`
+
+// convertedTransactionSelect is transactionSelect plus each amount converted
+// into the currency bound to param, read into ConvertedAmount. It is used
+// when a filter has ConvertTo set.
+func convertedTransactionSelect(param string) string {
+	return `
+	SELECT ` + transactionColumns + `, t.amount * fx.rate` + transactionFrom + fxRateJoin(param)
+}
+
+// scanTransaction reads a row selected with transactionSelect; extra
+// receives any columns selected after it.
+func scanTransaction(row pgx.Row, t *domain.Transaction, extra ...any) error {
+	return row.Scan(append([]any{
+		&t.ID, &t.Type, &t.CategoryID, &t.CategoryName, &t.AccountID, &t.AccountName, &t.PayeeID, &t.PayeeName,
+		&t.Amount, &t.Currency, &t.Description, &t.Status,
+		&t.Date, &t.TransferID, &t.Direction, &t.Tags, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt, &t.Splits,
+	}, extra...)...)
+}
+
+// selectFiltered returns the SELECT for listing or exporting with filter,
+// binding convert_to after args when conversion is requested, and a scan
+// function matching it.
+func selectFiltered(filter domain.TransactionFilter, args []interface{}) (string, []interface{}, func(pgx.Row, *domain.Transaction) error) {
+	if filter.ConvertTo == "" {
+		return transactionSelect, args, func(row pgx.Row, t *domain.Transaction) error {
+			return scanTransaction(row, t)
+		}
+	}
+	args = append(args, filter.ConvertTo)
+	query := convertedTransactionSelect(fmt.Sprintf("$%d", len(args)))
+	return query, args, func(row pgx.Row, t *domain.Transaction) error {
+		if err := scanTransaction(row, t, &t.ConvertedAmount); err != nil {
+			return err
+		}
+		t.ConvertedCurrency = filter.ConvertTo
+		return nil
+	}
+}
+
 type TransactionRepository struct {
 	db *pgxpool.Pool
 }
@@ -18,7 +91,7 @@ func NewTransactionRepository(db *pgxpool.Pool) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (r *TransactionRepository) Create(ctx context.Context, req domain.CreateTransactionRequest) (*domain.Transaction, error) {
+func (r *TransactionRepository) Create(ctx context.Context, ownerID string, req domain.CreateTransactionRequest) (*domain.Transaction, error) {
 	// Set defaults
 	if req.Currency == "" {
 		req.Currency = "IDR"
@@ -30,64 +103,134 @@ func (r *TransactionRepository) Create(ctx context.Context, req domain.CreateTra
 		req.Date = "now()"
 	}
 
-	var t domain.Transaction
-	err := r.db.QueryRow(ctx,
-		`INSERT INTO transactions (type, category_id, amount, currency, description, status, date)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
-		 RETURNING id, type, category_id, amount, currency, description, status, date::text, created_at, updated_at`,
-		req.Type, req.CategoryID, req.Amount, req.Currency, req.Description, req.Status, req.Date,
-	).Scan(&t.ID, &t.Type, &t.CategoryID, &t.Amount, &t.Currency, &t.Description, &t.Status, &t.Date, &t.CreatedAt, &t.UpdatedAt)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &t, nil
+	defer tx.Rollback(ctx)
+
+	var id string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (type, category_id, account_id, payee_id, amount, currency, description, status, date, owner_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 RETURNING id`,
+		req.Type, req.CategoryID, req.AccountID, req.PayeeID, req.Amount, req.Currency, req.Description, req.Status, req.Date, ownerID,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if err := setTransactionTags(ctx, tx, ownerID, id, req.Tags); err != nil {
+		return nil, err
+	}
+	if err := setTransactionSplits(ctx, tx, id, req.Splits); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
 }
 
-func (r *TransactionRepository) GetAll(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, int, error) {
-	// Set defaults
-	if filter.Page < 1 {
-		filter.Page = 1
+// setTransactionTags replaces the tags of a transaction with the given
+// names, creating the owner's tags that don't exist yet.
+func setTransactionTags(ctx context.Context, tx pgx.Tx, ownerID, transactionID string, names []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM transaction_tags WHERE transaction_id = $1`, transactionID); err != nil {
+		return err
 	}
-	if filter.Limit < 1 || filter.Limit > 100 {
-		filter.Limit = 20
+	return addTransactionTags(ctx, tx, ownerID, transactionID, names)
+}
+
+// addTransactionTags adds tags to a transaction, keeping the ones it already
+// carries and creating the owner's tags that don't exist yet.
+func addTransactionTags(ctx context.Context, tx pgx.Tx, ownerID, transactionID string, names []string) error {
+	names = domain.NormalizeTags(names)
+	if len(names) == 0 {
+		return nil
 	}
-	offset := (filter.Page - 1) * filter.Limit
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO tags (owner_id, name) SELECT $1, unnest($2::text[])
+		 ON CONFLICT (owner_id, name) DO NOTHING`, ownerID, names); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx,
+		`INSERT INTO transaction_tags (transaction_id, tag_id)
+		 SELECT $1, id FROM tags WHERE owner_id = $2 AND name = ANY($3)
+		 ON CONFLICT DO NOTHING`, transactionID, ownerID, names)
+	return err
+}
 
-	// Build WHERE clause dynamically
-	var conditions []string
-	var args []interface{}
-	argIdx := 1
+// setTransactionSplits replaces the split lines of a transaction, in order.
+func setTransactionSplits(ctx context.Context, tx pgx.Tx, transactionID string, splits []domain.SplitRequest) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, transactionID); err != nil {
+		return err
+	}
 
-	if filter.Type != "" {
-		conditions = append(conditions, fmt.Sprintf("t.type = $%d", argIdx))
-		args = append(args, filter.Type)
-		argIdx++
+	batch := &pgx.Batch{}
+	for i, split := range splits {
+		batch.Queue(
+			`INSERT INTO transaction_splits (transaction_id, position, category_id, amount, note)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			transactionID, i+1, split.CategoryID, split.Amount, split.Note,
+		)
 	}
-	if filter.CategoryID != "" {
-		conditions = append(conditions, fmt.Sprintf("t.category_id = $%d", argIdx))
-		args = append(args, filter.CategoryID)
-		argIdx++
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// CreateBatch inserts all transactions, with their payees and tags, in a
+// single database transaction; either every row is stored or none is.
+// Returns the IDs of the inserted transactions, in the order of reqs.
+func (r *TransactionRepository) CreateBatch(ctx context.Context, ownerID string, reqs []domain.CreateTransactionRequest) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if filter.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("t.status = $%d", argIdx))
-		args = append(args, filter.Status)
-		argIdx++
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, req := range reqs {
+		batch.Queue(
+			`INSERT INTO transactions (type, category_id, account_id, payee_id, amount, currency, description, status, date, owner_id)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			 RETURNING id`,
+			req.Type, req.CategoryID, req.AccountID, req.PayeeID, req.Amount, req.Currency, req.Description, req.Status, req.Date, ownerID,
+		)
 	}
-	if filter.DateFrom != "" {
-		conditions = append(conditions, fmt.Sprintf("t.date >= $%d", argIdx))
-		args = append(args, filter.DateFrom)
-		argIdx++
+	ids := make([]string, len(reqs))
+	results := tx.SendBatch(ctx, batch)
+	for i := range reqs {
+		if err := results.QueryRow().Scan(&ids[i]); err != nil {
+			results.Close()
+			return nil, err
+		}
 	}
-	if filter.DateTo != "" {
-		conditions = append(conditions, fmt.Sprintf("t.date <= $%d", argIdx))
-		args = append(args, filter.DateTo)
-		argIdx++
+	if err := results.Close(); err != nil {
+		return nil, err
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	for i, req := range reqs {
+		if err := addTransactionTags(ctx, tx, ownerID, ids[i], req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
 	}
+	return ids, nil
+}
+
+func (r *TransactionRepository) GetAll(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.Transaction, int, error) {
+	// Set defaults
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	offset := (filter.Page - 1) * filter.Limit
+
+	whereClause, args := transactionWhere(ownerID, filter)
 
 	// Count total
 	var total int
@@ -96,12 +239,10 @@ func (r *TransactionRepository) GetAll(ctx context.Context, filter domain.Transa
 		return nil, 0, err
 	}
 
-	// Fetch data with JOIN to get category name
-	query := fmt.Sprintf(`
-		SELECT t.id, t.type, t.category_id, c.name, t.amount, t.currency,
-		       t.description, t.status, t.date::text, t.created_at, t.updated_at
-		FROM transactions t
-		JOIN categories c ON c.id = t.category_id
+	// Fetch data with JOIN to get category and account names
+	selectClause, args, scan := selectFiltered(filter, args)
+	argIdx := len(args) + 1
+	query := fmt.Sprintf(selectClause+`
 		%s
 		ORDER BY t.date DESC, t.created_at DESC
 		LIMIT $%d OFFSET $%d`,
@@ -118,11 +259,7 @@ func (r *TransactionRepository) GetAll(ctx context.Context, filter domain.Transa
 	var transactions []domain.Transaction
 	for rows.Next() {
 		var t domain.Transaction
-		if err := rows.Scan(
-			&t.ID, &t.Type, &t.CategoryID, &t.CategoryName,
-			&t.Amount, &t.Currency, &t.Description, &t.Status,
-			&t.Date, &t.CreatedAt, &t.UpdatedAt,
-		); err != nil {
+		if err := scan(rows, &t); err != nil {
 			return nil, 0, err
 		}
 		transactions = append(transactions, t)
@@ -130,62 +267,310 @@ func (r *TransactionRepository) GetAll(ctx context.Context, filter domain.Transa
 	return transactions, total, nil
 }
 
-func (r *TransactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
+// Export streams every transaction matching the filter to fn, one row at a
+// time as pgx reads it, without pagination. Rows come oldest first.
+func (r *TransactionRepository) Export(ctx context.Context, ownerID string, filter domain.TransactionFilter, fn func(domain.Transaction) error) error {
+	whereClause, args := transactionWhere(ownerID, filter)
+	selectClause, args, scan := selectFiltered(filter, args)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(selectClause+`
+		%s
+		ORDER BY t.date, t.created_at`, whereClause), args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t domain.Transaction
+		if err := scan(rows, &t); err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// transactionWhere builds the WHERE clause for the owner's transactions
+// matching a filter, using positional args starting at $1. It is shared by
+// listing and reporting so the same query parameters select the same rows
+// everywhere. A split transaction matches category_id when any of its lines
+// does.
+func transactionWhere(ownerID string, filter domain.TransactionFilter) (string, []interface{}) {
+	return filterWhere(ownerID, filter, false)
+}
+
+// filterWhere is transactionWhere for queries that read a transaction once
+// per split line, joined as s. With lines set, category_id is matched
+// against each line, or the transaction itself when it isn't split.
+func filterWhere(ownerID string, filter domain.TransactionFilter, lines bool) (string, []interface{}) {
+	conditions := []string{"t.owner_id = $1"}
+	args := []interface{}{ownerID}
+	argIdx := 2
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "t.deleted_at IS NULL")
+	}
+
+	if filter.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("t.type = $%d", argIdx))
+		args = append(args, filter.Type)
+		argIdx++
+	}
+	if filter.CategoryID != "" {
+		match := fmt.Sprintf("= $%d", argIdx)
+		if filter.IncludeSubcategories {
+			match = "IN (" + categorySubtree(fmt.Sprintf("$%d", argIdx)) + ")"
+		}
+		if lines {
+			conditions = append(conditions, "COALESCE(s.category_id, t.category_id) "+match)
+		} else {
+			conditions = append(conditions, fmt.Sprintf(`(t.category_id %[1]s OR EXISTS (
+				SELECT 1 FROM transaction_splits ts WHERE ts.transaction_id = t.id AND ts.category_id %[1]s))`, match))
+		}
+		args = append(args, filter.CategoryID)
+		argIdx++
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, transactionHasTag(fmt.Sprintf("g.name = $%d", argIdx)))
+		args = append(args, domain.NormalizeTag(filter.Tag))
+		argIdx++
+	}
+	if tags := domain.NormalizeTags(filter.TagsAny); len(tags) > 0 {
+		conditions = append(conditions, transactionHasTag(fmt.Sprintf("g.name = ANY($%d)", argIdx)))
+		args = append(args, tags)
+		argIdx++
+	}
+	if tags := domain.NormalizeTags(filter.TagsAll); len(tags) > 0 {
+		// Every tag must be present: count the matching ones
+		conditions = append(conditions, fmt.Sprintf(`(SELECT COUNT(*) FROM transaction_tags tt JOIN tags g ON g.id = tt.tag_id
+			WHERE tt.transaction_id = t.id AND g.name = ANY($%[1]d)) = cardinality($%[1]d::text[])`, argIdx))
+		args = append(args, tags)
+		argIdx++
+	}
+	if filter.AccountID != "" {
+		conditions = append(conditions, fmt.Sprintf("t.account_id = $%d", argIdx))
+		args = append(args, filter.AccountID)
+		argIdx++
+	}
+	if filter.PayeeID != "" {
+		conditions = append(conditions, fmt.Sprintf("t.payee_id = $%d", argIdx))
+		args = append(args, filter.PayeeID)
+		argIdx++
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", argIdx))
+		args = append(args, filter.Status)
+		argIdx++
+	}
+	if filter.DateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("t.date >= $%d", argIdx))
+		args = append(args, filter.DateFrom)
+		argIdx++
+	}
+	if filter.DateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("t.date <= $%d", argIdx))
+		args = append(args, filter.DateTo)
+		argIdx++
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *TransactionRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Transaction, error) {
 	var t domain.Transaction
-	err := r.db.QueryRow(ctx,
-		`SELECT t.id, t.type, t.category_id, c.name, t.amount, t.currency,
-		        t.description, t.status, t.date::text, t.created_at, t.updated_at
-		 FROM transactions t
-		 JOIN categories c ON c.id = t.category_id
-		 WHERE t.id = $1`, id,
-	).Scan(&t.ID, &t.Type, &t.CategoryID, &t.CategoryName, &t.Amount, &t.Currency,
-		&t.Description, &t.Status, &t.Date, &t.CreatedAt, &t.UpdatedAt)
+	err := scanTransaction(r.db.QueryRow(ctx, transactionSelect+`
+		WHERE t.id = $1 AND t.owner_id = $2 AND t.deleted_at IS NULL`, id, ownerID,
+	), &t)
 	if err != nil {
 		return nil, err
 	}
 	return &t, nil
 }
 
-func (r *TransactionRepository) Update(ctx context.Context, id string, req domain.UpdateTransactionRequest) (*domain.Transaction, error) {
+func (r *TransactionRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateTransactionRequest) (*domain.Transaction, error) {
 	if req.Type != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET type = $1, updated_at = now() WHERE id = $2`, *req.Type, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET type = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Type, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.CategoryID != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET category_id = $1, updated_at = now() WHERE id = $2`, *req.CategoryID, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET category_id = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.CategoryID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.AccountID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET account_id = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.AccountID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.PayeeID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET payee_id = NULLIF($1, '')::uuid, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.PayeeID, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.Amount != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET amount = $1, updated_at = now() WHERE id = $2`, *req.Amount, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET amount = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Amount, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.Currency != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET currency = $1, updated_at = now() WHERE id = $2`, *req.Currency, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET currency = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Currency, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.Description != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET description = $1, updated_at = now() WHERE id = $2`, *req.Description, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET description = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Description, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.Status != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET status = $1, updated_at = now() WHERE id = $2`, *req.Status, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET status = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Status, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
 	if req.Date != nil {
-		if _, err := r.db.Exec(ctx, `UPDATE transactions SET date = $1, updated_at = now() WHERE id = $2`, *req.Date, id); err != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE transactions SET date = $1, updated_at = now() WHERE id = $2 AND owner_id = $3`, *req.Date, id, ownerID); err != nil {
 			return nil, err
 		}
 	}
-	return r.GetByID(ctx, id)
+	if req.Tags != nil || req.Splits != nil {
+		if err := r.updateTagsAndSplits(ctx, ownerID, id, req); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByID(ctx, ownerID, id)
 }
 
-func (r *TransactionRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM transactions WHERE id = $1`, id)
-	return err
+// updateTagsAndSplits replaces the tags and split lines given in req in a
+// single database transaction.
+func (r *TransactionRepository) updateTagsAndSplits(ctx context.Context, ownerID, id string, req domain.UpdateTransactionRequest) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE transactions SET updated_at = now() WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	if req.Tags != nil {
+		if err := setTransactionTags(ctx, tx, ownerID, id, *req.Tags); err != nil {
+			return err
+		}
+	}
+	if req.Splits != nil {
+		if err := setTransactionSplits(ctx, tx, id, *req.Splits); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ApplyRuleChanges saves what the rules change on the owner's transactions
+// in a single database transaction. Tags are added to the ones already
+// there.
+func (r *TransactionRepository) ApplyRuleChanges(ctx context.Context, ownerID string, changes []domain.RuleChange) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, change := range changes {
+		_, err := tx.Exec(ctx,
+			`UPDATE transactions
+			 SET category_id = COALESCE($1, category_id), payee_id = COALESCE($2, payee_id),
+			     status = COALESCE($3::transaction_status, status), updated_at = now()
+			 WHERE id = $4 AND owner_id = $5`,
+			change.CategoryID, change.PayeeID, change.Status, change.TransactionID, ownerID)
+		if err != nil {
+			return err
+		}
+		if err := addTransactionTags(ctx, tx, ownerID, change.TransactionID, change.AddTags); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Delete soft-deletes a transaction; it disappears from every read until
+// restored or purged.
+func (r *TransactionRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE transactions SET deleted_at = now() WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Restore brings back a soft-deleted transaction. Transfer legs are only
+// restored together, through TransferRepository.Restore.
+func (r *TransactionRepository) Restore(ctx context.Context, ownerID, id string) (*domain.Transaction, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE transactions SET deleted_at = NULL, updated_at = now()
+		 WHERE id = $1 AND owner_id = $2 AND deleted_at IS NOT NULL AND transfer_id IS NULL`, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Purge permanently removes transactions deleted before cutoff, together
+// with their attachments, in a single database transaction. Returns the
+// number of transactions removed and the storage keys of the attachment
+// files, which are left for the caller to delete.
+func (r *TransactionRepository) Purge(ctx context.Context, cutoff time.Time) (int64, []string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the transactions keeps them from being restored before
+	// they're deleted below.
+	rows, err := tx.Query(ctx,
+		`DELETE FROM attachments
+		 WHERE transaction_id IN (SELECT id FROM transactions WHERE deleted_at < $1 FOR UPDATE)
+		 RETURNING storage_key`, cutoff,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM transactions WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return tag.RowsAffected(), keys, nil
 }
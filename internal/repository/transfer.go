@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TransferRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTransferRepository(db *pgxpool.Pool) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// Create inserts both legs of a transfer in a single database transaction.
+// currency is the shared currency of the two accounts.
+func (r *TransferRepository) Create(ctx context.Context, ownerID string, req domain.CreateTransferRequest, currency string) (*domain.Transfer, error) {
+	if req.Status == "" {
+		req.Status = "completed"
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var transferID string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (type, account_id, amount, currency, description, status, date, transfer_id, transfer_direction, owner_id)
+		 VALUES ('transfer', $1, $2, $3, $4, $5, COALESCE(NULLIF($6, '')::date, CURRENT_DATE), gen_random_uuid(), 'out', $7)
+		 RETURNING transfer_id`,
+		req.FromAccountID, req.Amount, currency, req.Description, req.Status, req.Date, ownerID,
+	).Scan(&transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO transactions (type, account_id, amount, currency, description, status, date, transfer_id, transfer_direction, owner_id)
+		 SELECT 'transfer', $1, amount, currency, description, status, date, transfer_id, 'in', owner_id
+		 FROM transactions WHERE transfer_id = $2 AND transfer_direction = 'out'`,
+		req.ToAccountID, transferID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, transferID)
+}
+
+func (r *TransferRepository) GetAll(ctx context.Context, ownerID string, filter domain.TransferFilter) ([]domain.Transfer, int, error) {
+	// Set defaults
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	offset := (filter.Page - 1) * filter.Limit
+
+	// Filter on the outgoing leg; account_id matches either side of the transfer
+	conditions := []string{"t.owner_id = $1", "t.type = 'transfer'", "t.transfer_direction = 'out'", "t.deleted_at IS NULL"}
+	args := []interface{}{ownerID}
+	argIdx := 2
+
+	if filter.AccountID != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"t.transfer_id IN (SELECT transfer_id FROM transactions WHERE type = 'transfer' AND account_id = $%d)", argIdx))
+		args = append(args, filter.AccountID)
+		argIdx++
+	}
+	if filter.DateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("t.date >= $%d", argIdx))
+		args = append(args, filter.DateFrom)
+		argIdx++
+	}
+	if filter.DateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("t.date <= $%d", argIdx))
+		args = append(args, filter.DateTo)
+		argIdx++
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM transactions t %s`, whereClause)
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(transactionSelect+`
+		WHERE t.owner_id = $1 AND t.deleted_at IS NULL AND t.transfer_id IN (
+			SELECT t.transfer_id FROM transactions t
+			%s
+			ORDER BY t.date DESC, t.created_at DESC
+			LIMIT $%d OFFSET $%d
+		)
+		ORDER BY t.date DESC, t.created_at DESC, t.transfer_id, t.transfer_direction DESC`,
+		whereClause, argIdx, argIdx+1,
+	)
+	args = append(args, filter.Limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transfers, err := collectTransfers(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return transfers, total, nil
+}
+
+func (r *TransferRepository) GetByID(ctx context.Context, ownerID, id string) (*domain.Transfer, error) {
+	rows, err := r.db.Query(ctx, transactionSelect+`
+		WHERE t.transfer_id = $1 AND t.owner_id = $2 AND t.deleted_at IS NULL`, id, ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers, err := collectTransfers(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(transfers) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return &transfers[0], nil
+}
+
+// Update applies the changes to both legs in a single database transaction.
+func (r *TransferRepository) Update(ctx context.Context, ownerID, id string, req domain.UpdateTransferRequest) (*domain.Transfer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if req.FromAccountID != nil {
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET account_id = $1, updated_at = now() WHERE transfer_id = $2 AND transfer_direction = 'out' AND owner_id = $3`, *req.FromAccountID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.ToAccountID != nil {
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET account_id = $1, updated_at = now() WHERE transfer_id = $2 AND transfer_direction = 'in' AND owner_id = $3`, *req.ToAccountID, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Amount != nil {
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET amount = $1, updated_at = now() WHERE transfer_id = $2 AND owner_id = $3`, *req.Amount, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET description = $1, updated_at = now() WHERE transfer_id = $2 AND owner_id = $3`, *req.Description, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != nil {
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET status = $1, updated_at = now() WHERE transfer_id = $2 AND owner_id = $3`, *req.Status, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Date != nil {
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET date = $1, updated_at = now() WHERE transfer_id = $2 AND owner_id = $3`, *req.Date, id, ownerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// Delete soft-deletes both legs of the transfer.
+func (r *TransferRepository) Delete(ctx context.Context, ownerID, id string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE transactions SET deleted_at = now() WHERE transfer_id = $1 AND owner_id = $2 AND deleted_at IS NULL`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Restore brings back both legs of a soft-deleted transfer.
+func (r *TransferRepository) Restore(ctx context.Context, ownerID, id string) (*domain.Transfer, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE transactions SET deleted_at = NULL, updated_at = now()
+		 WHERE transfer_id = $1 AND owner_id = $2 AND deleted_at IS NOT NULL`, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return r.GetByID(ctx, ownerID, id)
+}
+
+// collectTransfers pairs up transfer legs, keeping the order in which each
+// transfer first appears in rows.
+func collectTransfers(rows pgx.Rows) ([]domain.Transfer, error) {
+	var transfers []domain.Transfer
+	index := make(map[string]int)
+	for rows.Next() {
+		var t domain.Transaction
+		if err := scanTransaction(rows, &t); err != nil {
+			return nil, err
+		}
+		if t.TransferID == nil || t.Direction == nil {
+			continue
+		}
+
+		i, ok := index[*t.TransferID]
+		if !ok {
+			i = len(transfers)
+			index[*t.TransferID] = i
+			transfers = append(transfers, domain.Transfer{ID: *t.TransferID})
+		}
+		if *t.Direction == "out" {
+			transfers[i].From = t
+		} else {
+			transfers[i].To = t
+		}
+	}
+	return transfers, rows.Err()
+}
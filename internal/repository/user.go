@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserRepository(db *pgxpool.Pool) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, email, name, passwordHash string) (*domain.User, error) {
+	var u domain.User
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO users (email, name, password_hash) VALUES ($1, $2, $3)
+		 RETURNING id, email, name, created_at, updated_at`,
+		email, name, passwordHash,
+	).Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	var u domain.User
+	err := r.db.QueryRow(ctx,
+		`SELECT id, email, name, created_at, updated_at FROM users WHERE id = $1`, id,
+	).Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetByEmail returns the user and their password hash for login.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, string, error) {
+	var u domain.User
+	var passwordHash string
+	err := r.db.QueryRow(ctx,
+		`SELECT id, email, name, created_at, updated_at, password_hash FROM users WHERE email = $1`, email,
+	).Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt, &passwordHash)
+	if err != nil {
+		return nil, "", err
+	}
+	return &u, passwordHash, nil
+}
+
+func (r *UserRepository) CreateRefreshToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		userID, tokenHash, expiresAt,
+	)
+	return err
+}
+
+func (r *UserRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var t domain.RefreshToken
+	err := r.db.QueryRow(ctx,
+		`SELECT id, user_id, expires_at, revoked_at FROM refresh_tokens WHERE token_hash = $1`, tokenHash,
+	).Scan(&t.ID, &t.UserID, &t.ExpiresAt, &t.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// RotateRefreshToken revokes the token with oldID and stores its replacement
+// in one database transaction. It returns pgx.ErrNoRows if the old token was
+// already revoked, so a token can only ever be exchanged once.
+func (r *UserRepository) RotateRefreshToken(ctx context.Context, oldID, userID, newHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var newID string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)
+		 RETURNING id`,
+		userID, newHash, expiresAt,
+	).Scan(&newID)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now(), replaced_by = $1
+		 WHERE id = $2 AND revoked_at IS NULL`,
+		newID, oldID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeRefreshToken revokes a single token, e.g. on logout.
+func (r *UserRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE token_hash = $1 AND revoked_at IS NULL`, tokenHash,
+	)
+	return err
+}
+
+// RevokeUserRefreshTokens revokes every active token of a user.
+func (r *UserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID,
+	)
+	return err
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrUnknownAccount is returned when a request refers to an account that
+// doesn't exist or belongs to someone else.
+var ErrUnknownAccount = errors.New("account not found")
+
+type AccountService struct {
+	repo *repository.AccountRepository
+}
+
+func NewAccountService(repo *repository.AccountRepository) *AccountService {
+	return &AccountService{repo: repo}
+}
+
+func (s *AccountService) Create(ctx context.Context, ownerID string, req domain.CreateAccountRequest) (*domain.Account, error) {
+	if req.Currency == "" {
+		req.Currency = "IDR"
+	}
+	if err := domain.ValidateAmount(req.OpeningBalance, req.Currency); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, ownerID, req)
+}
+
+func (s *AccountService) GetAll(ctx context.Context, ownerID string) ([]domain.Account, error) {
+	return s.repo.GetAll(ctx, ownerID)
+}
+
+func (s *AccountService) GetByID(ctx context.Context, ownerID, id string) (*domain.Account, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *AccountService) Update(ctx context.Context, ownerID, id string, req domain.UpdateAccountRequest) (*domain.Account, error) {
+	if req.OpeningBalance != nil {
+		existing, err := s.repo.GetByID(ctx, ownerID, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := domain.ValidateAmount(*req.OpeningBalance, existing.Currency); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Update(ctx, ownerID, id, req)
+}
+
+func (s *AccountService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// lookupAccount returns one of the owner's accounts, or ErrUnknownAccount.
+func lookupAccount(ctx context.Context, repo *repository.AccountRepository, ownerID, id string) (*domain.Account, error) {
+	account, err := repo.GetByID(ctx, ownerID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUnknownAccount
+	}
+	return account, err
+}
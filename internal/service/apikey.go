@@ -4,18 +4,24 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"time"
 
 	"personal-finance-backend/internal/domain"
 	"personal-finance-backend/internal/repository"
 )
 
 type ApiKeyService struct {
-	repo *repository.ApiKeyRepository
+	repo        *repository.ApiKeyRepository
+	audit       *AuditService
+	gracePeriod time.Duration
 }
 
-func NewApiKeyService(repo *repository.ApiKeyRepository) *ApiKeyService {
-	return &ApiKeyService{repo: repo}
+// NewApiKeyService creates the service. gracePeriod is how long a rotated
+// out secret keeps working by default.
+func NewApiKeyService(repo *repository.ApiKeyRepository, audit *AuditService, gracePeriod time.Duration) *ApiKeyService {
+	return &ApiKeyService{repo: repo, audit: audit, gracePeriod: gracePeriod}
 }
 
 // generateKey creates a cryptographically secure random 64-char hex key.
@@ -27,12 +33,33 @@ func generateKey() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+// hashToken returns the hex SHA-256 of a secret token. API keys and refresh
+// tokens are stored only in this form.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// keyPrefixLen is how many leading characters of an API key are kept in
+// clear text to identify it.
+const keyPrefixLen = 8
+
 func (s *ApiKeyService) Create(ctx context.Context, req domain.CreateApiKeyRequest) (*domain.ApiKey, error) {
 	key, err := generateKey()
 	if err != nil {
 		return nil, err
 	}
-	return s.repo.Create(ctx, req.Name, key)
+	if req.Scopes == nil {
+		req.Scopes = []string{domain.ScopeAll}
+	}
+	apiKey, err := s.repo.Create(ctx, hashToken(key), key[:keyPrefixLen], req)
+	if err != nil {
+		return nil, err
+	}
+	// Audited before the secret is filled in, so it never reaches the log
+	s.audit.Record(ctx, "create", auditApiKey, apiKey.ID, nil, apiKey)
+	apiKey.Key = key
+	return apiKey, nil
 }
 
 func (s *ApiKeyService) GetAll(ctx context.Context) ([]domain.ApiKey, error) {
@@ -44,13 +71,54 @@ func (s *ApiKeyService) GetByID(ctx context.Context, id string) (*domain.ApiKey,
 }
 
 func (s *ApiKeyService) Update(ctx context.Context, id string, req domain.UpdateApiKeyRequest) (*domain.ApiKey, error) {
-	return s.repo.Update(ctx, id, req)
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := s.repo.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "update", auditApiKey, id, before, apiKey)
+	return apiKey, nil
+}
+
+// Rotate issues a new secret for a key. The old one keeps working for the
+// requested grace period, or the configured default.
+func (s *ApiKeyService) Rotate(ctx context.Context, id string, req domain.RotateApiKeyRequest) (*domain.ApiKey, error) {
+	key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+	grace := s.gracePeriod
+	if req.GracePeriodMinutes != nil {
+		grace = time.Duration(*req.GracePeriodMinutes) * time.Minute
+	}
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := s.repo.Rotate(ctx, id, hashToken(key), key[:keyPrefixLen], time.Now().Add(grace), req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "rotate", auditApiKey, id, before, apiKey)
+	apiKey.Key = key
+	return apiKey, nil
 }
 
 func (s *ApiKeyService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, "delete", auditApiKey, id, before, nil)
+	return nil
 }
 
 func (s *ApiKeyService) ValidateKey(ctx context.Context, key string) (*domain.ApiKey, error) {
-	return s.repo.ValidateKey(ctx, key)
+	return s.repo.ValidateKey(ctx, hashToken(key))
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+	"personal-finance-backend/internal/storage"
+)
+
+// ErrAttachmentTooLarge is returned when an upload is over the configured
+// size limit.
+var ErrAttachmentTooLarge = errors.New("attachment is too large")
+
+// ErrAttachmentEmpty is returned when an upload has no content.
+var ErrAttachmentEmpty = errors.New("attachment is empty")
+
+// ErrAttachmentType is returned when the content of an upload isn't one of
+// the accepted file types.
+var ErrAttachmentType = errors.New("attachment type is not accepted")
+
+// sniffLen is how much of an upload http.DetectContentType looks at.
+const sniffLen = 512
+
+// AttachmentService stores files attached to transactions. Metadata goes to
+// the database and content to the storage. Files stay while their
+// transaction is soft-deleted, so that restoring it brings them back, and are
+// removed by the purge.
+type AttachmentService struct {
+	repo            *repository.AttachmentRepository
+	transactionRepo *repository.TransactionRepository
+	storage         storage.Storage
+	maxSize         int64
+	types           []string
+}
+
+func NewAttachmentService(repo *repository.AttachmentRepository, transactionRepo *repository.TransactionRepository,
+	store storage.Storage, maxSize int64, types []string) *AttachmentService {
+	return &AttachmentService{repo: repo, transactionRepo: transactionRepo, storage: store, maxSize: maxSize, types: types}
+}
+
+// MaxSize is the largest accepted attachment, in bytes.
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Create stores size bytes read from r as an attachment of one of the
+// owner's transactions. The file type is sniffed from the content and the
+// SHA-256 computed while the file is written to storage.
+func (s *AttachmentService) Create(ctx context.Context, ownerID, transactionID, filename string, r io.Reader, size int64) (*domain.Attachment, error) {
+	tx, err := s.transactionRepo.GetByID(ctx, ownerID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.TransferID != nil {
+		return nil, ErrTransferLeg
+	}
+	if size <= 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if size > s.maxSize {
+		return nil, fmt.Errorf("%w, the limit is %d MB", ErrAttachmentTooLarge, s.maxSize>>20)
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !slices.Contains(s.types, contentType) {
+		return nil, fmt.Errorf("%w, use one of %s", ErrAttachmentType, strings.Join(s.types, ", "))
+	}
+
+	key, err := attachmentKey(ownerID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	content := io.TeeReader(io.MultiReader(bytes.NewReader(head), r), hash)
+	if err := s.storage.Put(ctx, key, content, size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.repo.Create(ctx, domain.Attachment{
+		TransactionID: transactionID,
+		Filename:      attachmentFilename(filename),
+		ContentType:   contentType,
+		Size:          size,
+		SHA256:        hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:    key,
+	})
+	if err != nil {
+		s.deleteFile(ctx, key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (s *AttachmentService) GetAll(ctx context.Context, ownerID, transactionID string) ([]domain.Attachment, error) {
+	if _, err := s.transactionRepo.GetByID(ctx, ownerID, transactionID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, ownerID, transactionID)
+}
+
+// Open returns an attachment along with its content, which the caller must
+// close.
+func (s *AttachmentService) Open(ctx context.Context, ownerID, transactionID, id string) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetByID(ctx, ownerID, transactionID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// Delete removes an attachment and its file.
+func (s *AttachmentService) Delete(ctx context.Context, ownerID, transactionID, id string) error {
+	attachment, err := s.repo.Delete(ctx, ownerID, transactionID, id)
+	if err != nil {
+		return err
+	}
+	s.deleteFile(ctx, attachment.StorageKey)
+	return nil
+}
+
+// deleteFile removes a file whose metadata is already gone. A failure only
+// leaves an unreferenced file behind, so it is logged rather than returned.
+func (s *AttachmentService) deleteFile(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("attachments: deleting %s: %v", key, err)
+	}
+}
+
+// attachmentKey returns a new storage key for a file of the transaction. The
+// uploaded filename is kept out of it.
+func attachmentKey(ownerID, transactionID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return path.Join(ownerID, transactionID, hex.EncodeToString(b)), nil
+}
+
+// attachmentFilename keeps the last element of an uploaded filename, cut to
+// fit the filename column.
+func attachmentFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+)
+
+// Audited entity types.
+const (
+	auditCategory    = "category"
+	auditTransaction = "transaction"
+	auditTransfer    = "transfer"
+	auditApiKey      = "api_key"
+)
+
+type AuditService struct {
+	repo *repository.AuditRepository
+}
+
+func NewAuditService(repo *repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record logs a change made by the actor in ctx. before is nil for creates
+// and after is nil for deletes. The change has already happened, so a
+// failure to write the entry is logged rather than returned.
+func (s *AuditService) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+	actor := domain.ActorFrom(ctx)
+	entry := domain.AuditEntry{
+		ActorType:  actor.Type,
+		ActorName:  actor.Name,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+	if actor.ID != "" {
+		entry.ActorID = &actor.ID
+	}
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			log.Printf("audit: %s %s %s: %v", action, entityType, entityID, err)
+			return
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			log.Printf("audit: %s %s %s: %v", action, entityType, entityID, err)
+			return
+		}
+	}
+
+	// Keep the entry even when the request is cancelled right after the change
+	if err := s.repo.Create(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("audit: %s %s %s: %v", action, entityType, entityID, err)
+	}
+}
+
+func (s *AuditService) GetAll(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int, error) {
+	return s.repo.GetAll(ctx, filter)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrEmailTaken is returned when registering an email that already has a user.
+	ErrEmailTaken = errors.New("email is already registered")
+	// ErrInvalidCredentials is returned for an unknown email or a wrong password.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidRefreshToken is returned for unknown, expired or already used
+	// refresh tokens.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrInvalidAccessToken is returned when an access token fails verification.
+	ErrInvalidAccessToken = errors.New("invalid or expired access token")
+	// ErrJWTNotConfigured is returned when JWT_SECRET is empty.
+	ErrJWTNotConfigured = errors.New("JWT authentication is not configured")
+)
+
+// dummyPasswordHash is compared against when the email is unknown, so a
+// failed login takes as long whether or not the user exists.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type AuthService struct {
+	repo       *repository.UserRepository
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewAuthService(repo *repository.UserRepository, secret string, accessTTL, refreshTTL time.Duration) *AuthService {
+	return &AuthService{repo: repo, secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest) (*domain.AuthTokens, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrJWTNotConfigured
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.Create(ctx, normalizeEmail(req.Email), strings.TrimSpace(req.Name), string(hash))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrEmailTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, user)
+}
+
+func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthTokens, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrJWTNotConfigured
+	}
+	user, hash, err := s.repo.GetByEmail(ctx, normalizeEmail(req.Email))
+	if errors.Is(err, pgx.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return s.issue(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token. The
+// presented token is revoked. Presenting a token that was already used
+// revokes every token of the user, since it has probably been stolen.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrJWTNotConfigured
+	}
+	stored, err := s.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.RevokedAt != nil {
+		if err := s.repo.RevokeUserRefreshTokens(ctx, stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.refreshTTL)
+	err = s.repo.RotateRefreshToken(ctx, stored.ID, user.ID, hashToken(newToken), expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Lost a race with another refresh using the same token
+		if err := s.repo.RevokeUserRefreshTokens(ctx, stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokens(user, newToken, expiresAt)
+}
+
+// Logout revokes a refresh token. Access tokens stay valid until they expire.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	return s.repo.RevokeRefreshToken(ctx, hashToken(refreshToken))
+}
+
+func (s *AuthService) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ValidateAccessToken verifies an access token and returns its user ID.
+func (s *AuthService) ValidateAccessToken(token string) (string, error) {
+	if len(s.secret) == 0 {
+		return "", ErrJWTNotConfigured
+	}
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || claims.Subject == "" {
+		return "", ErrInvalidAccessToken
+	}
+	return claims.Subject, nil
+}
+
+// issue starts a new session for user with a fresh refresh token.
+func (s *AuthService) issue(ctx context.Context, user *domain.User) (*domain.AuthTokens, error) {
+	refreshToken, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.refreshTTL)
+	if err := s.repo.CreateRefreshToken(ctx, user.ID, hashToken(refreshToken), expiresAt); err != nil {
+		return nil, err
+	}
+	return s.tokens(user, refreshToken, expiresAt)
+}
+
+// tokens signs an access token for user and bundles it with refreshToken.
+func (s *AuthService) tokens(user *domain.User, refreshToken string, refreshExpiresAt time.Time) (*domain.AuthTokens, error) {
+	now := time.Now()
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   user.ID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+	}).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.accessTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             user,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrBudgetCategoryType is returned when a budget is set on a non-expense category.
+var ErrBudgetCategoryType = errors.New("budgets can only be set on expense categories")
+
+type BudgetService struct {
+	repo         *repository.BudgetRepository
+	categoryRepo *repository.CategoryRepository
+}
+
+func NewBudgetService(repo *repository.BudgetRepository, categoryRepo *repository.CategoryRepository) *BudgetService {
+	return &BudgetService{repo: repo, categoryRepo: categoryRepo}
+}
+
+func (s *BudgetService) Create(ctx context.Context, ownerID string, req domain.CreateBudgetRequest) (*domain.Budget, error) {
+	category, err := lookupCategory(ctx, s.categoryRepo, ownerID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.Type != "expense" {
+		return nil, ErrBudgetCategoryType
+	}
+	if req.Currency == "" {
+		req.Currency = "IDR"
+	}
+	if err := domain.ValidateAmount(req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, ownerID, req)
+}
+
+func (s *BudgetService) GetAll(ctx context.Context, ownerID string) ([]domain.Budget, error) {
+	return s.repo.GetAll(ctx, ownerID)
+}
+
+func (s *BudgetService) GetByID(ctx context.Context, ownerID, id string) (*domain.Budget, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *BudgetService) Update(ctx context.Context, ownerID, id string, req domain.UpdateBudgetRequest) (*domain.Budget, error) {
+	if req.Amount != nil {
+		existing, err := s.repo.GetByID(ctx, ownerID, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := domain.ValidateAmount(*req.Amount, existing.Currency); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Update(ctx, ownerID, id, req)
+}
+
+func (s *BudgetService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// Status reports spent and remaining amounts of every budget of the owner
+// for the given month, given as its first day. Budgets that start after the
+// month are left out.
+func (s *BudgetService) Status(ctx context.Context, ownerID string, month time.Time) ([]domain.BudgetStatus, error) {
+	budgets, err := s.repo.GetAll(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	spending, err := s.repo.MonthlySpending(ctx, ownerID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []domain.BudgetStatus{}
+	for _, b := range budgets {
+		start, err := time.Parse("2006-01", b.StartMonth)
+		if err != nil {
+			return nil, err
+		}
+		if start.After(month) {
+			continue
+		}
+
+		// Walk the months before the requested one, carrying whatever was
+		// left unspent. Overspending never reduces the next month's limit.
+		rolledOver := decimal.Zero
+		if b.Rollover {
+			for m := start; m.Before(month); m = m.AddDate(0, 1, 0) {
+				left := b.Amount.Add(rolledOver).Sub(spending[b.ID][m.Format("2006-01")])
+				rolledOver = decimal.Max(left, decimal.Zero)
+			}
+		}
+
+		spent := spending[b.ID][month.Format("2006-01")]
+		available := b.Amount.Add(rolledOver)
+		statuses = append(statuses, domain.BudgetStatus{
+			BudgetID:     b.ID,
+			CategoryID:   b.CategoryID,
+			CategoryName: b.CategoryName,
+			Currency:     b.Currency,
+			Month:        month.Format("2006-01"),
+			Limit:        b.Amount,
+			RolledOver:   rolledOver,
+			Available:    available,
+			Spent:        spent,
+			Remaining:    available.Sub(spent),
+		})
+	}
+	return statuses, nil
+}
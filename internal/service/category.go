@@ -2,35 +2,287 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"personal-finance-backend/internal/domain"
 	"personal-finance-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// ErrUnknownCategory is returned when a request refers to a category that
+// doesn't exist or belongs to someone else.
+var ErrUnknownCategory = errors.New("category not found")
+
+// ErrSharedCategory is returned when changing or deleting one of the shared
+// default categories.
+var ErrSharedCategory = errors.New("shared categories cannot be changed")
+
+// ErrCategoryInUse is returned when deleting a category that transactions,
+// recurring rules or subcategories still use without saying where to move
+// them.
+var ErrCategoryInUse = errors.New("category is still in use, pass reassign_to to move its transactions and subcategories to another category")
+
+// ErrCategoryTypeMismatch is returned when moving transactions between an
+// income and an expense category.
+var ErrCategoryTypeMismatch = errors.New("both categories must have the same type")
+
+// ErrSameCategory is returned when merging a category into itself.
+var ErrSameCategory = errors.New("a category cannot be merged into itself")
+
+// ErrCategoryCycle is returned when a category would end up below itself.
+var ErrCategoryCycle = errors.New("a category cannot be placed under itself or one of its subcategories")
+
+// ErrParentType is returned when a subcategory's type would differ from its
+// parent's.
+var ErrParentType = errors.New("a subcategory must have the same type as its parent")
+
 type CategoryService struct {
-	repo *repository.CategoryRepository
+	repo  *repository.CategoryRepository
+	audit *AuditService
 }
 
-func NewCategoryService(repo *repository.CategoryRepository) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(repo *repository.CategoryRepository, audit *AuditService) *CategoryService {
+	return &CategoryService{repo: repo, audit: audit}
 }
 
-func (s *CategoryService) Create(ctx context.Context, req domain.CreateCategoryRequest) (*domain.Category, error) {
-	return s.repo.Create(ctx, req)
+func (s *CategoryService) Create(ctx context.Context, ownerID string, req domain.CreateCategoryRequest) (*domain.Category, error) {
+	if req.ParentID != nil {
+		parent, err := lookupCategory(ctx, s.repo, ownerID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Type != req.Type {
+			return nil, ErrParentType
+		}
+	}
+	category, err := s.repo.Create(ctx, ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "create", auditCategory, category.ID, nil, category)
+	return category, nil
 }
 
-func (s *CategoryService) GetAll(ctx context.Context) ([]domain.Category, error) {
-	return s.repo.GetAll(ctx)
+// GetAll lists the categories the owner can use, nested under their parents
+// with filter.Tree.
+func (s *CategoryService) GetAll(ctx context.Context, ownerID string, filter domain.CategoryFilter) ([]domain.Category, error) {
+	categories, err := s.repo.GetAll(ctx, ownerID, filter)
+	if err != nil || !filter.Tree {
+		return categories, err
+	}
+	return categoryTree(categories), nil
+}
+
+func (s *CategoryService) GetByID(ctx context.Context, ownerID, id string) (*domain.Category, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *CategoryService) Update(ctx context.Context, ownerID, id string, req domain.UpdateCategoryRequest) (*domain.Category, error) {
+	before, err := s.getOwned(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPlacement(ctx, ownerID, before, req); err != nil {
+		return nil, err
+	}
+	category, err := s.repo.Update(ctx, ownerID, id, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "update", auditCategory, id, before, category)
+	return category, nil
+}
+
+// Delete deletes one of the owner's categories. A category that is still in
+// use is only deleted with reassign_to, after moving everything to that
+// category; otherwise ErrCategoryInUse is returned along with the usage.
+// On success the returned usage is what was moved, if anything.
+func (s *CategoryService) Delete(ctx context.Context, ownerID, id string, req domain.DeleteCategoryRequest) (*domain.CategoryUsage, error) {
+	before, err := s.getOwned(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ReassignTo != "" {
+		if _, err := s.mergeTarget(ctx, ownerID, before, req.ReassignTo); err != nil {
+			return nil, err
+		}
+		moved, err := s.repo.Merge(ctx, ownerID, id, req.ReassignTo)
+		if err != nil {
+			return nil, err
+		}
+		s.audit.Record(ctx, "delete", auditCategory, id, before, nil)
+		return &moved, nil
+	}
+
+	usage, err := s.repo.Usage(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if usage.Transactions > 0 || usage.RecurringTransactions > 0 || usage.Subcategories > 0 {
+		return &usage, ErrCategoryInUse
+	}
+	if err := s.repo.Delete(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "delete", auditCategory, id, before, nil)
+	return nil, nil
+}
+
+// Merge folds one of the owner's categories into another of the same type:
+// its transactions, recurring rules and budget move to the target and the
+// category is deleted. Returns the target.
+func (s *CategoryService) Merge(ctx context.Context, ownerID, id string, req domain.MergeCategoryRequest) (*domain.Category, error) {
+	source, err := s.getOwned(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.mergeTarget(ctx, ownerID, source, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Merge(ctx, ownerID, id, target.ID); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "merge", auditCategory, id, source, target)
+	return target, nil
+}
+
+// mergeTarget returns the category that source's transactions and
+// subcategories can be moved to: one of the same type that the owner can use
+// and that isn't in source's subtree.
+func (s *CategoryService) mergeTarget(ctx context.Context, ownerID string, source *domain.Category, targetID string) (*domain.Category, error) {
+	if targetID == source.ID {
+		return nil, ErrSameCategory
+	}
+	target, err := lookupCategory(ctx, s.repo, ownerID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Type != source.Type {
+		return nil, ErrCategoryTypeMismatch
+	}
+	inSubtree, err := s.repo.IsInSubtree(ctx, source.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if inSubtree {
+		return nil, ErrCategoryCycle
+	}
+	return target, nil
+}
+
+// checkPlacement checks that an update keeps the tree valid: the new parent
+// exists, isn't in the category's subtree and has the category's type, and
+// a category with subcategories keeps its type.
+func (s *CategoryService) checkPlacement(ctx context.Context, ownerID string, category *domain.Category, req domain.UpdateCategoryRequest) error {
+	typ := category.Type
+	if req.Type != nil {
+		typ = *req.Type
+	}
+	parentID := category.ParentID
+	if req.ParentID != nil {
+		parentID = req.ParentID
+		if *req.ParentID == "" {
+			parentID = nil
+		}
+	}
+
+	if parentID != nil {
+		parent, err := lookupCategory(ctx, s.repo, ownerID, *parentID)
+		if err != nil {
+			return err
+		}
+		if req.ParentID != nil {
+			inSubtree, err := s.repo.IsInSubtree(ctx, category.ID, parent.ID)
+			if err != nil {
+				return err
+			}
+			if inSubtree {
+				return ErrCategoryCycle
+			}
+		}
+		if parent.Type != typ {
+			return ErrParentType
+		}
+	}
+
+	if typ != category.Type {
+		usage, err := s.repo.Usage(ctx, ownerID, category.ID)
+		if err != nil {
+			return err
+		}
+		if usage.Subcategories > 0 {
+			return ErrParentType
+		}
+	}
+	return nil
+}
+
+// categoryTree nests categories under their parents, keeping their order.
+// Categories whose parent isn't listed, such as a deleted one, become roots.
+func categoryTree(categories []domain.Category) []domain.Category {
+	listed := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		listed[c.ID] = true
+	}
+
+	children := map[string][]int{}
+	var roots []int
+	for i, c := range categories {
+		if c.ParentID != nil && listed[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(i int) domain.Category
+	build = func(i int) domain.Category {
+		c := categories[i]
+		for _, j := range children[c.ID] {
+			c.Children = append(c.Children, build(j))
+		}
+		return c
+	}
+
+	tree := []domain.Category{}
+	for _, i := range roots {
+		tree = append(tree, build(i))
+	}
+	return tree
 }
 
-func (s *CategoryService) GetByID(ctx context.Context, id string) (*domain.Category, error) {
-	return s.repo.GetByID(ctx, id)
+// Restore undoes the soft delete of one of the owner's categories.
+func (s *CategoryService) Restore(ctx context.Context, ownerID, id string) (*domain.Category, error) {
+	category, err := s.repo.Restore(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "restore", auditCategory, id, nil, category)
+	return category, nil
 }
 
-func (s *CategoryService) Update(ctx context.Context, id string, req domain.UpdateCategoryRequest) (*domain.Category, error) {
-	return s.repo.Update(ctx, id, req)
+// getOwned returns one of the owner's own categories: pgx.ErrNoRows for
+// categories the owner can't see and ErrSharedCategory for the shared ones.
+func (s *CategoryService) getOwned(ctx context.Context, ownerID, id string) (*domain.Category, error) {
+	category, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if category.Shared {
+		return nil, ErrSharedCategory
+	}
+	return category, nil
 }
 
-func (s *CategoryService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+// lookupCategory returns a category the owner can use, either their own or a
+// shared one, or ErrUnknownCategory.
+func lookupCategory(ctx context.Context, repo *repository.CategoryRepository, ownerID, id string) (*domain.Category, error) {
+	category, err := repo.GetByID(ctx, ownerID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUnknownCategory
+	}
+	return category, err
 }
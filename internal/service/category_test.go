@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"personal-finance-backend/internal/domain"
+)
+
+func TestCategoryTree(t *testing.T) {
+	cat := func(id string, parentID *string) domain.Category {
+		return domain.Category{ID: id, Name: id, ParentID: parentID}
+	}
+
+	tests := []struct {
+		name       string
+		categories []domain.Category
+		want       string
+	}{
+		{"empty", nil, ""},
+		{"flat keeps order", []domain.Category{cat("b", nil), cat("a", nil)}, "b a"},
+		{
+			"nested",
+			[]domain.Category{cat("food", nil), cat("coffee", ptr("food")), cat("lunch", ptr("food")), cat("salary", nil)},
+			"food(coffee lunch) salary",
+		},
+		{
+			"grandchildren listed before their parent",
+			[]domain.Category{cat("beans", ptr("coffee")), cat("food", nil), cat("coffee", ptr("food"))},
+			"food(coffee(beans))",
+		},
+		{
+			"unlisted parent becomes a root",
+			[]domain.Category{cat("coffee", ptr("deleted")), cat("food", nil)},
+			"coffee food",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderTree(categoryTree(tt.categories)); got != tt.want {
+				t.Errorf("categoryTree() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// renderTree writes a tree as "parent(child child) parent".
+func renderTree(categories []domain.Category) string {
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+		if len(c.Children) > 0 {
+			names[i] += "(" + renderTree(c.Children) + ")"
+		}
+	}
+	return strings.Join(names, " ")
+}
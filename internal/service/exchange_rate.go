@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+)
+
+// ErrSameCurrency is returned when an exchange rate converts a currency into
+// itself, e.g. when from_currency is the base currency and to_currency is
+// left out.
+var ErrSameCurrency = errors.New("from_currency and to_currency must differ")
+
+// withConvertTo resolves convert_to=base to the configured base currency.
+func withConvertTo(filter domain.TransactionFilter, baseCurrency string) domain.TransactionFilter {
+	if filter.ConvertTo == "base" {
+		filter.ConvertTo = baseCurrency
+	}
+	return filter
+}
+
+type ExchangeRateService struct {
+	repo         *repository.ExchangeRateRepository
+	baseCurrency string
+}
+
+func NewExchangeRateService(repo *repository.ExchangeRateRepository, baseCurrency string) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo, baseCurrency: baseCurrency}
+}
+
+// withDefaults fills in the base currency as to_currency when none is given.
+func (s *ExchangeRateService) withDefaults(req domain.CreateExchangeRateRequest) (domain.CreateExchangeRateRequest, error) {
+	if req.ToCurrency == "" {
+		req.ToCurrency = s.baseCurrency
+	}
+	if req.FromCurrency == req.ToCurrency {
+		return req, ErrSameCurrency
+	}
+	return req, nil
+}
+
+func (s *ExchangeRateService) Create(ctx context.Context, ownerID string, req domain.CreateExchangeRateRequest) (*domain.ExchangeRate, error) {
+	req, err := s.withDefaults(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, ownerID, req)
+}
+
+// BulkCreate stores all rates or none. Rates that already exist for the same
+// pair and date are replaced.
+func (s *ExchangeRateService) BulkCreate(ctx context.Context, ownerID string, req domain.BulkCreateExchangeRatesRequest) (int, error) {
+	rates := make([]domain.CreateExchangeRateRequest, len(req.Rates))
+	for i, r := range req.Rates {
+		r, err := s.withDefaults(r)
+		if err != nil {
+			return 0, fmt.Errorf("rates[%d]: %w", i, err)
+		}
+		rates[i] = r
+	}
+	return s.repo.Upsert(ctx, ownerID, rates)
+}
+
+func (s *ExchangeRateService) GetAll(ctx context.Context, ownerID string, filter domain.ExchangeRateFilter) ([]domain.ExchangeRate, int, error) {
+	return s.repo.GetAll(ctx, ownerID, filter)
+}
+
+func (s *ExchangeRateService) GetByID(ctx context.Context, ownerID, id string) (*domain.ExchangeRate, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *ExchangeRateService) Update(ctx context.Context, ownerID, id string, req domain.UpdateExchangeRateRequest) (*domain.ExchangeRate, error) {
+	if _, err := s.repo.GetByID(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, ownerID, id, req)
+}
+
+func (s *ExchangeRateService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
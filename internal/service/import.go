@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// maxImportRows caps the number of data rows in a single import.
+const maxImportRows = 5000
+
+var (
+	// ErrImportMapping is returned when the column mapping doesn't fit the file.
+	ErrImportMapping = errors.New("invalid import mapping")
+	// ErrImportInvalidRows is returned alongside the result when a real
+	// (non dry-run) import has invalid rows. Nothing is imported.
+	ErrImportInvalidRows = errors.New("import has invalid rows, nothing was imported")
+)
+
+// importValidator checks each parsed row against the binding tags of
+// CreateTransactionRequest, the same rules POST /transactions applies.
+var importValidator = newImportValidator()
+
+func newImportValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if d, ok := field.Interface().(decimal.Decimal); ok {
+			f, _ := d.Float64()
+			return f
+		}
+		return nil
+	}, decimal.Decimal{})
+	return v
+}
+
+type ImportService struct {
+	transactionRepo *repository.TransactionRepository
+	categoryRepo    *repository.CategoryRepository
+	accountRepo     *repository.AccountRepository
+	ruleRepo        *repository.RuleRepository
+	audit           *AuditService
+}
+
+func NewImportService(transactionRepo *repository.TransactionRepository, categoryRepo *repository.CategoryRepository,
+	accountRepo *repository.AccountRepository, ruleRepo *repository.RuleRepository, audit *AuditService) *ImportService {
+	return &ImportService{transactionRepo: transactionRepo, categoryRepo: categoryRepo, accountRepo: accountRepo, ruleRepo: ruleRepo,
+		audit: audit}
+}
+
+// Import parses a bank statement CSV with the given mapping and validates
+// every row against the same rules as POST /transactions. The owner's
+// categorization rules fill in each row first; the mapping's default
+// categories and status cover what they leave unset. With dryRun the
+// parsed rows and per-row errors are returned without touching the
+// database; otherwise all rows are inserted in one database transaction,
+// and only if every row is valid. Each imported transaction is audited.
+func (s *ImportService) Import(ctx context.Context, ownerID string, file io.Reader, mapping domain.ImportMapping, dryRun bool) (*domain.ImportResult, error) {
+	if err := s.prepareMapping(ctx, ownerID, &mapping); err != nil {
+		return nil, err
+	}
+	rules, err := loadRules(ctx, s.ruleRepo, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // statements often have preamble and footer lines of any width
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := findImportHeader(reader, mapping)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := importColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ImportResult{DryRun: dryRun, Errors: []domain.ImportRowError{}}
+	var reqs []domain.CreateTransactionRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImportMapping, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		result.TotalRows++
+		if result.TotalRows > maxImportRows {
+			return nil, fmt.Errorf("%w: file has more than %d rows", ErrImportMapping, maxImportRows)
+		}
+
+		req, err := parseImportRow(record, columns, mapping)
+		if err == nil {
+			rules.fill(&req)
+			err = importDefaults(&req, mapping)
+		}
+		if err == nil {
+			err = importValidator.Struct(&req)
+		}
+		if err == nil {
+			err = domain.ValidateAmount(req.Amount, req.Currency)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, domain.ImportRowError{Row: line, Error: err.Error()})
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+	result.ValidRows = len(reqs)
+
+	if dryRun {
+		result.Transactions = reqs
+		return result, nil
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrImportInvalidRows
+	}
+	if len(reqs) > 0 {
+		ids, err := s.transactionRepo.CreateBatch(ctx, ownerID, reqs)
+		if err != nil {
+			return nil, err
+		}
+		for i, id := range ids {
+			s.audit.Record(ctx, "import", auditTransaction, id, nil, reqs[i])
+		}
+		result.Imported = len(ids)
+	}
+	return result, nil
+}
+
+// prepareMapping fills in defaults and checks the referenced categories and
+// account before any row is parsed.
+func (s *ImportService) prepareMapping(ctx context.Context, ownerID string, m *domain.ImportMapping) error {
+	if m.DateFormat == "" {
+		m.DateFormat = "2006-01-02"
+	}
+	if m.SignConvention == "" {
+		m.SignConvention = "negative_expense"
+	}
+	if m.DecimalSeparator == "" {
+		m.DecimalSeparator = "."
+	}
+	if m.ThousandsSeparator == "" {
+		m.ThousandsSeparator = ","
+		if m.DecimalSeparator == "," {
+			m.ThousandsSeparator = "."
+		}
+	}
+	if m.ThousandsSeparator == m.DecimalSeparator {
+		return fmt.Errorf("%w: decimal and thousands separators must differ", ErrImportMapping)
+	}
+	if m.Status == "" {
+		m.Status = "completed"
+	}
+
+	defaults := []struct {
+		categoryID   *string
+		categoryType string
+	}{
+		{m.DefaultIncomeCategoryID, "income"},
+		{m.DefaultExpenseCategoryID, "expense"},
+	}
+	for _, d := range defaults {
+		if d.categoryID == nil {
+			continue
+		}
+		category, err := s.categoryRepo.GetByID(ctx, ownerID, *d.categoryID)
+		if err != nil {
+			return fmt.Errorf("%w: category %s not found", ErrImportMapping, *d.categoryID)
+		}
+		if category.Type != d.categoryType {
+			return fmt.Errorf("%w: default %s category must be an %s category", ErrImportMapping, d.categoryType, d.categoryType)
+		}
+	}
+
+	if m.AccountID != nil {
+		account, err := s.accountRepo.GetByID(ctx, ownerID, *m.AccountID)
+		if err != nil {
+			return fmt.Errorf("%w: account %s not found", ErrImportMapping, *m.AccountID)
+		}
+		if m.Currency == "" {
+			m.Currency = account.Currency
+		}
+		if m.Currency != account.Currency {
+			return fmt.Errorf("%w: %v", ErrImportMapping, ErrCurrencyMismatch)
+		}
+	}
+	if m.Currency == "" {
+		m.Currency = "IDR"
+	}
+	return nil
+}
+
+// maxPreambleRows is how far into the file the header row is searched for.
+const maxPreambleRows = 50
+
+// findImportHeader skips statement preamble (account holder, period, ...)
+// up to the first row that contains both the date and amount columns.
+func findImportHeader(reader *csv.Reader, m domain.ImportMapping) ([]string, error) {
+	for i := 0; i < maxPreambleRows; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImportMapping, err)
+		}
+
+		hasDate, hasAmount := false, false
+		for _, name := range record {
+			name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+			hasDate = hasDate || name == m.DateColumn
+			hasAmount = hasAmount || name == m.AmountColumn
+		}
+		if hasDate && hasAmount {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no header row with columns %q and %q found", ErrImportMapping, m.DateColumn, m.AmountColumn)
+}
+
+type importColumnIndex struct {
+	date, amount, description, indicator int // -1 when not mapped
+}
+
+func importColumns(header []string, m domain.ImportMapping) (importColumnIndex, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		index[name] = i
+	}
+
+	lookup := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := index[name]
+		if !ok {
+			return -1, fmt.Errorf("%w: column %q not found in header", ErrImportMapping, name)
+		}
+		return i, nil
+	}
+
+	var cols importColumnIndex
+	var err error
+	if cols.date, err = lookup(m.DateColumn); err != nil {
+		return cols, err
+	}
+	if cols.amount, err = lookup(m.AmountColumn); err != nil {
+		return cols, err
+	}
+	if cols.description, err = lookup(m.DescriptionColumn); err != nil {
+		return cols, err
+	}
+	if cols.indicator, err = lookup(m.IndicatorColumn); err != nil {
+		return cols, err
+	}
+	return cols, nil
+}
+
+func parseImportRow(record []string, cols importColumnIndex, m domain.ImportMapping) (domain.CreateTransactionRequest, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := domain.CreateTransactionRequest{
+		AccountID: m.AccountID,
+		Currency:  m.Currency,
+	}
+
+	date, err := time.Parse(m.DateFormat, field(cols.date))
+	if err != nil {
+		return req, fmt.Errorf("invalid date %q, expected format %s", field(cols.date), m.DateFormat)
+	}
+	req.Date = date.Format("2006-01-02")
+
+	amount, err := parseImportAmount(field(cols.amount), m)
+	if err != nil {
+		return req, err
+	}
+	req.Amount = amount.Abs()
+
+	switch m.SignConvention {
+	case "indicator":
+		req.Type = "income"
+		if strings.EqualFold(field(cols.indicator), m.ExpenseIndicator) {
+			req.Type = "expense"
+		}
+	case "positive_expense":
+		req.Type = "expense"
+		if amount.IsNegative() {
+			req.Type = "income"
+		}
+	default: // negative_expense
+		req.Type = "income"
+		if amount.IsNegative() {
+			req.Type = "expense"
+		}
+	}
+
+	if desc := field(cols.description); desc != "" {
+		req.Description = &desc
+	}
+	return req, nil
+}
+
+// importDefaults fills in what neither the row nor the rules set: the
+// mapping's status and its default category for the row's type.
+func importDefaults(req *domain.CreateTransactionRequest, m domain.ImportMapping) error {
+	if req.Status == "" {
+		req.Status = m.Status
+	}
+	if req.CategoryID == "" {
+		if req.Type == "income" && m.DefaultIncomeCategoryID != nil {
+			req.CategoryID = *m.DefaultIncomeCategoryID
+		}
+		if req.Type == "expense" && m.DefaultExpenseCategoryID != nil {
+			req.CategoryID = *m.DefaultExpenseCategoryID
+		}
+	}
+	if req.CategoryID == "" {
+		return fmt.Errorf("no category for %s row, add a rule or set default_%s_category_id", req.Type, req.Type)
+	}
+	return nil
+}
+
+// parseImportAmount parses amounts like "-1.250.000,00", "(50,000.00)" or
+// "Rp 15.000" according to the mapping's separators. Parentheses mean a
+// negative amount.
+func parseImportAmount(raw string, m domain.ImportMapping) (decimal.Decimal, error) {
+	s := strings.TrimSpace(raw)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	s = strings.ReplaceAll(s, m.ThousandsSeparator, "")
+	s = strings.ReplaceAll(s, m.DecimalSeparator, ".")
+	s = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '+' {
+			return r
+		}
+		return -1 // drop currency symbols and spaces
+	}, s)
+
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", raw)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
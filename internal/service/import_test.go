@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseImportAmount(t *testing.T) {
+	dot := domain.ImportMapping{DecimalSeparator: ".", ThousandsSeparator: ","}
+	comma := domain.ImportMapping{DecimalSeparator: ",", ThousandsSeparator: "."}
+
+	tests := []struct {
+		name    string
+		raw     string
+		mapping domain.ImportMapping
+		want    string
+		wantErr bool
+	}{
+		{"plain", "15000", dot, "15000", false},
+		{"thousands and decimals", "50,000.00", dot, "50000", false},
+		{"negative", "-1,250.75", dot, "-1250.75", false},
+		{"explicit plus", "+12.5", dot, "12.5", false},
+		{"parentheses are negative", "(50,000.00)", dot, "-50000", false},
+		{"currency symbol and spaces", "Rp 15.000", comma, "15000", false},
+		{"comma decimals", "-1.250.000,00", comma, "-1250000", false},
+		{"comma decimals with cents", "12,34", comma, "12.34", false},
+		{"surrounding whitespace", "  7.5  ", dot, "7.5", false},
+		{"empty", "", dot, "", true},
+		{"no digits", "Rp", comma, "", true},
+		{"two decimal points", "1.2.3", dot, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseImportAmount(tt.raw, tt.mapping)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseImportAmount(%q) = %s, want an error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseImportAmount(%q) error = %v", tt.raw, err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("parseImportAmount(%q) = %s, want %s", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImportRowSignConvention(t *testing.T) {
+	cols := importColumnIndex{date: 0, amount: 1, description: -1, indicator: 2}
+	base := domain.ImportMapping{DateFormat: "2006-01-02", DecimalSeparator: ".", ThousandsSeparator: ",", ExpenseIndicator: "DB"}
+
+	tests := []struct {
+		convention string
+		amount     string
+		indicator  string
+		wantType   string
+	}{
+		{"negative_expense", "-10", "", "expense"},
+		{"negative_expense", "10", "", "income"},
+		{"positive_expense", "10", "", "expense"},
+		{"positive_expense", "-10", "", "income"},
+		{"indicator", "10", "DB", "expense"},
+		{"indicator", "10", "db", "expense"},
+		{"indicator", "10", "CR", "income"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.convention+" "+tt.amount+" "+tt.indicator, func(t *testing.T) {
+			m := base
+			m.SignConvention = tt.convention
+			req, err := parseImportRow([]string{"2026-02-01", tt.amount, tt.indicator}, cols, m)
+			if err != nil {
+				t.Fatalf("parseImportRow() error = %v", err)
+			}
+			if req.Type != tt.wantType {
+				t.Errorf("type = %s, want %s", req.Type, tt.wantType)
+			}
+			if !req.Amount.Equal(decimal.NewFromInt(10)) {
+				t.Errorf("amount = %s, want 10", req.Amount)
+			}
+		})
+	}
+}
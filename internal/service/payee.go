@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrUnknownPayee is returned when a request refers to a payee that doesn't
+// exist or belongs to someone else.
+var ErrUnknownPayee = errors.New("payee not found")
+
+// ErrPayeeNameEmpty is returned when a payee name is blank once trimmed.
+var ErrPayeeNameEmpty = errors.New("payee name cannot be blank")
+
+type PayeeService struct {
+	repo         *repository.PayeeRepository
+	categoryRepo *repository.CategoryRepository
+}
+
+func NewPayeeService(repo *repository.PayeeRepository, categoryRepo *repository.CategoryRepository) *PayeeService {
+	return &PayeeService{repo: repo, categoryRepo: categoryRepo}
+}
+
+func (s *PayeeService) Create(ctx context.Context, ownerID string, req domain.CreatePayeeRequest) (*domain.Payee, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, ErrPayeeNameEmpty
+	}
+	if req.DefaultCategoryID != nil {
+		if _, err := lookupCategory(ctx, s.categoryRepo, ownerID, *req.DefaultCategoryID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Create(ctx, ownerID, req)
+}
+
+func (s *PayeeService) GetAll(ctx context.Context, ownerID string) ([]domain.Payee, error) {
+	return s.repo.GetAll(ctx, ownerID)
+}
+
+func (s *PayeeService) GetByID(ctx context.Context, ownerID, id string) (*domain.Payee, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *PayeeService) Update(ctx context.Context, ownerID, id string, req domain.UpdatePayeeRequest) (*domain.Payee, error) {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrPayeeNameEmpty
+		}
+		req.Name = &name
+	}
+	if req.DefaultCategoryID != nil && *req.DefaultCategoryID != "" {
+		if _, err := lookupCategory(ctx, s.categoryRepo, ownerID, *req.DefaultCategoryID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Update(ctx, ownerID, id, req)
+}
+
+func (s *PayeeService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// lookupPayee returns one of the owner's payees, or ErrUnknownPayee.
+func lookupPayee(ctx context.Context, repo *repository.PayeeRepository, ownerID, id string) (*domain.Payee, error) {
+	payee, err := repo.GetByID(ctx, ownerID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUnknownPayee
+	}
+	return payee, err
+}
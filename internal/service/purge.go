@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"personal-finance-backend/internal/repository"
+	"personal-finance-backend/internal/storage"
+)
+
+// PurgeService permanently removes soft-deleted rows once their retention
+// period has passed.
+type PurgeService struct {
+	transactionRepo *repository.TransactionRepository
+	categoryRepo    *repository.CategoryRepository
+	storage         storage.Storage
+	retention       time.Duration
+}
+
+func NewPurgeService(transactionRepo *repository.TransactionRepository, categoryRepo *repository.CategoryRepository,
+	store storage.Storage, retention time.Duration) *PurgeService {
+	return &PurgeService{transactionRepo: transactionRepo, categoryRepo: categoryRepo, storage: store, retention: retention}
+}
+
+// PurgeDeleted removes transactions and categories deleted before
+// now minus the retention period. Transactions go first so that the
+// categories they referenced can be removed in the same run. The files
+// attached to those transactions are only removed once their rows are
+// gone, and a file that can't be removed is logged and left behind rather
+// than failing the run. Returns the number of transactions and categories
+// removed.
+func (s *PurgeService) PurgeDeleted(ctx context.Context, now time.Time) (int64, int64, error) {
+	cutoff := now.Add(-s.retention)
+
+	transactions, keys, err := s.transactionRepo.Purge(ctx, cutoff)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("purge: deleting %s: %v", key, err)
+		}
+	}
+	categories, err := s.categoryRepo.Purge(ctx, cutoff)
+	if err != nil {
+		return transactions, 0, err
+	}
+	return transactions, categories, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"personal-finance-backend/internal/repository"
+)
+
+// RateLimit is the outcome of taking a request from a token bucket that
+// holds Limit tokens and refills at Limit tokens per minute.
+type RateLimit struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Time     // when the bucket is full again
+	RetryAfter time.Duration // until the next token, when not allowed
+}
+
+// RateLimiter takes one request from the bucket of key.
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit int) (RateLimit, error)
+}
+
+// newRateLimit describes a bucket left with tokens after a request.
+func newRateLimit(limit int, tokens float64, allowed bool, now time.Time) RateLimit {
+	perSecond := float64(limit) / 60
+	rl := RateLimit{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(int(math.Floor(tokens)), 0),
+		Reset:     now.Add(time.Duration((float64(limit) - tokens) / perSecond * float64(time.Second))),
+	}
+	if !allowed {
+		rl.RetryAfter = time.Duration((1 - tokens) / perSecond * float64(time.Second))
+	}
+	return rl
+}
+
+// MemoryRateLimiter keeps the buckets in process memory, which is only
+// correct when a single machine serves the API.
+type MemoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: map[string]*tokenBucket{}}
+}
+
+func (l *MemoryRateLimiter) Take(_ context.Context, key string, limit int) (RateLimit, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit), updatedAt: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(float64(limit), b.tokens+now.Sub(b.updatedAt).Seconds()*float64(limit)/60)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return newRateLimit(limit, b.tokens, allowed, now), nil
+}
+
+// PostgresRateLimiter keeps the buckets in the rate_limit_buckets table so
+// that every machine shares them.
+type PostgresRateLimiter struct {
+	repo *repository.RateLimitRepository
+}
+
+func NewPostgresRateLimiter(repo *repository.RateLimitRepository) *PostgresRateLimiter {
+	return &PostgresRateLimiter{repo: repo}
+}
+
+func (l *PostgresRateLimiter) Take(ctx context.Context, key string, limit int) (RateLimit, error) {
+	tokens, allowed, err := l.repo.Take(ctx, key, limit)
+	if err != nil {
+		return RateLimit{}, err
+	}
+	return newRateLimit(limit, tokens, allowed, time.Now()), nil
+}
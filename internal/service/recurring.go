@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrInvalidSchedule is returned for recurrence rules that can't be scheduled.
+var ErrInvalidSchedule = errors.New("day_of_month is only valid for monthly and yearly rules, and end_date must not be before start_date")
+
+type RecurringService struct {
+	repo         *repository.RecurringRepository
+	categoryRepo *repository.CategoryRepository
+	accountRepo  *repository.AccountRepository
+}
+
+func NewRecurringService(repo *repository.RecurringRepository, categoryRepo *repository.CategoryRepository,
+	accountRepo *repository.AccountRepository) *RecurringService {
+	return &RecurringService{repo: repo, categoryRepo: categoryRepo, accountRepo: accountRepo}
+}
+
+func (s *RecurringService) Create(ctx context.Context, ownerID string, req domain.CreateRecurringTransactionRequest) (*domain.RecurringTransaction, error) {
+	if _, err := lookupCategory(ctx, s.categoryRepo, ownerID, req.CategoryID); err != nil {
+		return nil, err
+	}
+	if req.AccountID != nil {
+		account, err := lookupAccount(ctx, s.accountRepo, ownerID, *req.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if req.Currency == "" {
+			req.Currency = account.Currency
+		}
+		if req.Currency != account.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+	}
+	if req.Currency == "" {
+		req.Currency = "IDR"
+	}
+	if err := domain.ValidateAmount(req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+
+	sched, err := newSchedule(domain.RecurringTransaction{
+		Frequency:      req.Frequency,
+		DayOfMonth:     req.DayOfMonth,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		MaxOccurrences: req.MaxOccurrences,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, ownerID, req, sched.next(nil))
+}
+
+func (s *RecurringService) GetAll(ctx context.Context, ownerID string) ([]domain.RecurringTransaction, error) {
+	return s.repo.GetAll(ctx, ownerID)
+}
+
+func (s *RecurringService) GetByID(ctx context.Context, ownerID, id string) (*domain.RecurringTransaction, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *RecurringService) Update(ctx context.Context, ownerID, id string, req domain.UpdateRecurringTransactionRequest) (*domain.RecurringTransaction, error) {
+	rt, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != nil {
+		if _, err := lookupCategory(ctx, s.categoryRepo, ownerID, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	if req.AccountID != nil {
+		account, err := lookupAccount(ctx, s.accountRepo, ownerID, *req.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if rt.Currency != account.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+	}
+	if req.Amount != nil {
+		if err := domain.ValidateAmount(*req.Amount, rt.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	// Recompute the next occurrence from the merged schedule
+	if req.Frequency != nil {
+		rt.Frequency = *req.Frequency
+	}
+	if req.DayOfMonth != nil {
+		rt.DayOfMonth = req.DayOfMonth
+	}
+	if req.EndDate != nil {
+		rt.EndDate = req.EndDate
+	}
+	if req.MaxOccurrences != nil {
+		rt.MaxOccurrences = req.MaxOccurrences
+	}
+	sched, err := newSchedule(*rt)
+	if err != nil {
+		return nil, err
+	}
+	after, err := parseOptionalDate(rt.LastDate)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, ownerID, id, req, sched.next(after))
+}
+
+func (s *RecurringService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// Preview returns the next count occurrence dates of a rule, starting after
+// the last transaction it generated.
+func (s *RecurringService) Preview(ctx context.Context, ownerID, id string, count int) ([]string, error) {
+	rt, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := newSchedule(*rt)
+	if err != nil {
+		return nil, err
+	}
+	after, err := parseOptionalDate(rt.LastDate)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := []string{}
+	sched.each(after, func(d time.Time) bool {
+		dates = append(dates, d.Format(dateLayout))
+		return len(dates) < count
+	})
+	return dates, nil
+}
+
+// MaterializeDue creates the transactions of every rule that has come due
+// on or before today, catching up on any occurrences that were missed while
+// the worker wasn't running. A rule whose schedule can't be read is logged
+// and deactivated instead of failing the run. Returns the number of
+// transactions created.
+func (s *RecurringService) MaterializeDue(ctx context.Context, today time.Time) (int, error) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	return s.repo.MaterializeDue(ctx, today, func(rt domain.RecurringTransaction) ([]string, *string, error) {
+		dates, next, err := dueDates(rt, today)
+		if err != nil {
+			log.Printf("recurring: deactivating %s: %v", rt.ID, err)
+		}
+		return dates, next, err
+	})
+}
+
+// dueDates returns the occurrences of rt after its last one up to and
+// including today, and the first occurrence after today, if any.
+func dueDates(rt domain.RecurringTransaction, today time.Time) ([]string, *string, error) {
+	sched, err := newSchedule(rt)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := parseOptionalDate(rt.LastDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dates []string
+	var next *string
+	sched.each(after, func(d time.Time) bool {
+		if d.After(today) {
+			v := d.Format(dateLayout)
+			next = &v
+			return false
+		}
+		dates = append(dates, d.Format(dateLayout))
+		return true
+	})
+	return dates, next, nil
+}
+
+// schedule is the parsed recurrence of a rule.
+type schedule struct {
+	frequency  string
+	dayOfMonth int
+	start      time.Time
+	end        *time.Time
+	remaining  int // occurrences left to generate, -1 when unlimited
+}
+
+func newSchedule(rt domain.RecurringTransaction) (schedule, error) {
+	start, err := time.Parse(dateLayout, rt.StartDate)
+	if err != nil {
+		return schedule{}, err
+	}
+	end, err := parseOptionalDate(rt.EndDate)
+	if err != nil {
+		return schedule{}, err
+	}
+	if end != nil && end.Before(start) {
+		return schedule{}, ErrInvalidSchedule
+	}
+
+	s := schedule{frequency: rt.Frequency, dayOfMonth: start.Day(), start: start, end: end, remaining: -1}
+	if rt.DayOfMonth != nil {
+		if rt.Frequency != "monthly" && rt.Frequency != "yearly" {
+			return schedule{}, ErrInvalidSchedule
+		}
+		s.dayOfMonth = *rt.DayOfMonth
+	}
+	if rt.MaxOccurrences != nil {
+		s.remaining = max(*rt.MaxOccurrences-rt.Occurrences, 0)
+	}
+	return s, nil
+}
+
+// at returns the k-th candidate date of the schedule. Monthly and yearly
+// dates are computed from the start rather than from the previous date, so
+// a rule on the 31st comes back to the 31st after a short month.
+func (s schedule) at(k int) time.Time {
+	switch s.frequency {
+	case "daily":
+		return s.start.AddDate(0, 0, k)
+	case "weekly":
+		return s.start.AddDate(0, 0, 7*k)
+	case "monthly":
+		return clampedDate(s.start.Year(), s.start.Month()+time.Month(k), s.dayOfMonth)
+	default: // yearly
+		return clampedDate(s.start.Year()+k, s.start.Month(), s.dayOfMonth)
+	}
+}
+
+// each calls fn with every remaining occurrence after the given date (from
+// the start when nil), in order, until fn returns false or the schedule ends.
+func (s schedule) each(after *time.Time, fn func(time.Time) bool) {
+	count := 0
+	for k := 0; ; k++ {
+		d := s.at(k)
+		if d.Before(s.start) || (after != nil && !d.After(*after)) {
+			continue
+		}
+		if (s.end != nil && d.After(*s.end)) || (s.remaining >= 0 && count >= s.remaining) {
+			return
+		}
+		count++
+		if !fn(d) {
+			return
+		}
+	}
+}
+
+// next returns the first occurrence after the given date, or nil when the
+// schedule has finished.
+func (s schedule) next(after *time.Time) *string {
+	var next *string
+	s.each(after, func(d time.Time) bool {
+		v := d.Format(dateLayout)
+		next = &v
+		return false
+	})
+	return next
+}
+
+// clampedDate returns the given day of the month, or the month's last day
+// when it is shorter. month may overflow into following years.
+func clampedDate(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+func parseOptionalDate(s *string) (*time.Time, error) {
+	if s == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(dateLayout, *s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
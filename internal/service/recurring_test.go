@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"personal-finance-backend/internal/domain"
+)
+
+func TestDueDates(t *testing.T) {
+	day := func(d int) *int { return &d }
+	date := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		rt        domain.RecurringTransaction
+		today     string
+		wantDates []string
+		wantNext  *string
+	}{
+		{
+			name:      "daily from start",
+			rt:        domain.RecurringTransaction{Frequency: "daily", StartDate: "2026-01-01"},
+			today:     "2026-01-03",
+			wantDates: []string{"2026-01-01", "2026-01-02", "2026-01-03"},
+			wantNext:  date("2026-01-04"),
+		},
+		{
+			name:      "daily after last date",
+			rt:        domain.RecurringTransaction{Frequency: "daily", StartDate: "2026-01-01", LastDate: date("2026-01-02")},
+			today:     "2026-01-03",
+			wantDates: []string{"2026-01-03"},
+			wantNext:  date("2026-01-04"),
+		},
+		{
+			name:      "weekly",
+			rt:        domain.RecurringTransaction{Frequency: "weekly", StartDate: "2026-01-05"},
+			today:     "2026-01-20",
+			wantDates: []string{"2026-01-05", "2026-01-12", "2026-01-19"},
+			wantNext:  date("2026-01-26"),
+		},
+		{
+			name:      "monthly on the 31st clamps to short months and comes back",
+			rt:        domain.RecurringTransaction{Frequency: "monthly", StartDate: "2026-01-31"},
+			today:     "2026-04-30",
+			wantDates: []string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"},
+			wantNext:  date("2026-05-31"),
+		},
+		{
+			name:      "monthly day_of_month before the start day skips the first month",
+			rt:        domain.RecurringTransaction{Frequency: "monthly", StartDate: "2026-01-20", DayOfMonth: day(5)},
+			today:     "2026-03-01",
+			wantDates: []string{"2026-02-05"},
+			wantNext:  date("2026-03-05"),
+		},
+		{
+			name:      "yearly on 29 February in a leap year",
+			rt:        domain.RecurringTransaction{Frequency: "yearly", StartDate: "2024-02-29"},
+			today:     "2026-12-31",
+			wantDates: []string{"2024-02-29", "2025-02-28", "2026-02-28"},
+			wantNext:  date("2027-02-28"),
+		},
+		{
+			name:      "end date is inclusive and finishes the schedule",
+			rt:        domain.RecurringTransaction{Frequency: "daily", StartDate: "2026-01-01", EndDate: date("2026-01-02")},
+			today:     "2026-01-10",
+			wantDates: []string{"2026-01-01", "2026-01-02"},
+		},
+		{
+			name: "max occurrences counts the ones already generated",
+			rt: domain.RecurringTransaction{Frequency: "daily", StartDate: "2026-01-01", MaxOccurrences: day(3),
+				Occurrences: 2, LastDate: date("2026-01-02")},
+			today:     "2026-01-10",
+			wantDates: []string{"2026-01-03"},
+		},
+		{
+			name:     "not due yet",
+			rt:       domain.RecurringTransaction{Frequency: "monthly", StartDate: "2026-02-01"},
+			today:    "2026-01-15",
+			wantNext: date("2026-02-01"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			today, err := time.Parse(dateLayout, tt.today)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dates, next, err := dueDates(tt.rt, today)
+			if err != nil {
+				t.Fatalf("dueDates() error = %v", err)
+			}
+			if !slices.Equal(dates, tt.wantDates) {
+				t.Errorf("dates = %v, want %v", dates, tt.wantDates)
+			}
+			if (next == nil) != (tt.wantNext == nil) || (next != nil && *next != *tt.wantNext) {
+				t.Errorf("next = %v, want %v", deref(next), deref(tt.wantNext))
+			}
+		})
+	}
+}
+
+func TestNewScheduleErrors(t *testing.T) {
+	day := 10
+	end := "2026-01-01"
+
+	tests := []struct {
+		name    string
+		rt      domain.RecurringTransaction
+		wantErr error
+	}{
+		{"day_of_month on a daily rule", domain.RecurringTransaction{Frequency: "daily", StartDate: "2026-01-01", DayOfMonth: &day}, ErrInvalidSchedule},
+		{"day_of_month on a weekly rule", domain.RecurringTransaction{Frequency: "weekly", StartDate: "2026-01-01", DayOfMonth: &day}, ErrInvalidSchedule},
+		{"end before start", domain.RecurringTransaction{Frequency: "daily", StartDate: "2026-02-01", EndDate: &end}, ErrInvalidSchedule},
+		{"unparsable start date", domain.RecurringTransaction{Frequency: "daily", StartDate: "01/02/2026"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSchedule(tt.rt)
+			if err == nil {
+				t.Fatal("newSchedule() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("newSchedule() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClampedDate(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+		want  string
+	}{
+		{2026, time.January, 31, "2026-01-31"},
+		{2026, time.February, 31, "2026-02-28"},
+		{2024, time.February, 30, "2024-02-29"},
+		{2026, time.April, 31, "2026-04-30"},
+		{2026, 14, 31, "2027-02-28"}, // month overflows into the next year
+	}
+	for _, tt := range tests {
+		if got := clampedDate(tt.year, tt.month, tt.day).Format(dateLayout); got != tt.want {
+			t.Errorf("clampedDate(%d, %d, %d) = %s, want %s", tt.year, tt.month, tt.day, got, tt.want)
+		}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+)
+
+// ErrMissingExchangeRate is returned when a converted report includes
+// transactions that have no exchange rate into the requested currency.
+var ErrMissingExchangeRate = errors.New("no exchange rate effective on the transaction date")
+
+type ReportService struct {
+	repo         *repository.ReportRepository
+	baseCurrency string
+}
+
+func NewReportService(repo *repository.ReportRepository, baseCurrency string) *ReportService {
+	return &ReportService{repo: repo, baseCurrency: baseCurrency}
+}
+
+// Only completed transactions count towards reports unless a status is
+// requested explicitly.
+func withReportDefaults(filter domain.TransactionFilter) domain.TransactionFilter {
+	if filter.Status == "" {
+		filter.Status = "completed"
+	}
+	return filter
+}
+
+// prepare applies the report defaults and, when converting, makes sure every
+// transaction in the report can be converted so totals are never partial.
+func (s *ReportService) prepare(ctx context.Context, ownerID string, filter domain.TransactionFilter) (domain.TransactionFilter, error) {
+	filter = withConvertTo(withReportDefaults(filter), s.baseCurrency)
+	if filter.ConvertTo == "" {
+		return filter, nil
+	}
+	missing, err := s.repo.MissingRates(ctx, ownerID, filter)
+	if err != nil {
+		return filter, err
+	}
+	if missing > 0 {
+		return filter, fmt.Errorf("%w for %d transactions (%s)", ErrMissingExchangeRate, missing, filter.ConvertTo)
+	}
+	return filter, nil
+}
+
+func (s *ReportService) Summary(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.SummaryReport, error) {
+	filter, err := s.prepare(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.repo.Summary(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		sm := &summaries[i]
+		sm.Income = domain.RoundAmount(sm.Income, sm.Currency)
+		sm.Expense = domain.RoundAmount(sm.Expense, sm.Currency)
+		sm.Net = sm.Income.Sub(sm.Expense)
+	}
+	return summaries, nil
+}
+
+func (s *ReportService) ByCategory(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.CategoryReport, error) {
+	filter, err := s.prepare(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.ByCategory(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		reports[i].Total = domain.RoundAmount(reports[i].Total, reports[i].Currency)
+	}
+	return reports, nil
+}
+
+func (s *ReportService) ByTag(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.TagReport, error) {
+	filter, err := s.prepare(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.ByTag(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		reports[i].Total = domain.RoundAmount(reports[i].Total, reports[i].Currency)
+	}
+	return reports, nil
+}
+
+func (s *ReportService) ByPayee(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.PayeeReport, error) {
+	filter, err := s.prepare(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.ByPayee(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		reports[i].Total = domain.RoundAmount(reports[i].Total, reports[i].Currency)
+	}
+	return reports, nil
+}
+
+func (s *ReportService) MonthlyTrend(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.MonthlyReport, error) {
+	filter, err := s.prepare(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.MonthlyTrend(ctx, ownerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		m := &reports[i]
+		m.Income = domain.RoundAmount(m.Income, m.Currency)
+		m.Expense = domain.RoundAmount(m.Expense, m.Currency)
+		m.Net = m.Income.Sub(m.Expense)
+	}
+	return reports, nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRuleNoConditions is returned when a rule would match every transaction.
+var ErrRuleNoConditions = errors.New("a rule needs at least one condition")
+
+// ErrRuleNoActions is returned when a rule would change nothing.
+var ErrRuleNoActions = errors.New("a rule needs at least one action")
+
+// ErrRuleRegex is returned when description_regex doesn't compile.
+var ErrRuleRegex = errors.New("invalid description_regex")
+
+// ErrRuleAmountRange is returned when amount_min is above amount_max.
+var ErrRuleAmountRange = errors.New("amount_min cannot be greater than amount_max")
+
+// ErrRuleCategoryType is returned when a rule only matches one type but sets
+// a category of the other. It wraps ErrCategoryType.
+var ErrRuleCategoryType = fmt.Errorf("%w: conditions.type must match the type of actions.category_id", ErrCategoryType)
+
+// ErrDateRange is returned when date_from is after date_to.
+var ErrDateRange = errors.New("date_from cannot be after date_to")
+
+type RuleService struct {
+	repo            *repository.RuleRepository
+	transactionRepo *repository.TransactionRepository
+	categoryRepo    *repository.CategoryRepository
+	payeeRepo       *repository.PayeeRepository
+	audit           *AuditService
+}
+
+func NewRuleService(repo *repository.RuleRepository, transactionRepo *repository.TransactionRepository,
+	categoryRepo *repository.CategoryRepository, payeeRepo *repository.PayeeRepository, audit *AuditService) *RuleService {
+	return &RuleService{repo: repo, transactionRepo: transactionRepo, categoryRepo: categoryRepo, payeeRepo: payeeRepo, audit: audit}
+}
+
+func (s *RuleService) Create(ctx context.Context, ownerID string, req domain.CreateRuleRequest) (*domain.Rule, error) {
+	if err := s.check(ctx, ownerID, &req.Conditions, &req.Actions); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, ownerID, req)
+}
+
+// GetAll lists the owner's rules in the order they run.
+func (s *RuleService) GetAll(ctx context.Context, ownerID string) ([]domain.Rule, error) {
+	return s.repo.GetAll(ctx, ownerID)
+}
+
+func (s *RuleService) GetByID(ctx context.Context, ownerID, id string) (*domain.Rule, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *RuleService) Update(ctx context.Context, ownerID, id string, req domain.UpdateRuleRequest) (*domain.Rule, error) {
+	if req.Conditions != nil || req.Actions != nil {
+		existing, err := s.repo.GetByID(ctx, ownerID, id)
+		if err != nil {
+			return nil, err
+		}
+		conditions, actions := existing.Conditions, existing.Actions
+		if req.Conditions != nil {
+			conditions = *req.Conditions
+		}
+		if req.Actions != nil {
+			actions = *req.Actions
+		}
+		if err := s.check(ctx, ownerID, &conditions, &actions); err != nil {
+			return nil, err
+		}
+		// check may fill in the type, so both are saved
+		req.Conditions, req.Actions = &conditions, &actions
+	}
+	return s.repo.Update(ctx, ownerID, id, req)
+}
+
+func (s *RuleService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// check validates a rule's conditions and actions and normalizes its tags.
+// A rule that sets a category only matches transactions of the category's
+// type, so the type condition is filled in from it.
+func (s *RuleService) check(ctx context.Context, ownerID string, c *domain.RuleConditions, a *domain.RuleActions) error {
+	a.Tags = domain.NormalizeTags(a.Tags)
+
+	if c.DescriptionContains == nil && c.DescriptionRegex == nil && c.AmountMin == nil && c.AmountMax == nil && c.Type == nil {
+		return ErrRuleNoConditions
+	}
+	if a.CategoryID == nil && a.PayeeID == nil && len(a.Tags) == 0 && a.Status == nil {
+		return ErrRuleNoActions
+	}
+	if c.DescriptionRegex != nil {
+		if _, err := regexp.Compile(*c.DescriptionRegex); err != nil {
+			return fmt.Errorf("%w: %v", ErrRuleRegex, err)
+		}
+	}
+	if c.AmountMin != nil && c.AmountMax != nil && c.AmountMin.GreaterThan(*c.AmountMax) {
+		return ErrRuleAmountRange
+	}
+
+	if a.CategoryID != nil {
+		category, err := lookupCategory(ctx, s.categoryRepo, ownerID, *a.CategoryID)
+		if err != nil {
+			return err
+		}
+		if c.Type == nil {
+			c.Type = &category.Type
+		} else if *c.Type != category.Type {
+			return ErrRuleCategoryType
+		}
+	}
+	if a.PayeeID != nil {
+		if _, err := lookupPayee(ctx, s.payeeRepo, ownerID, *a.PayeeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply re-runs the owner's enabled rules over their transactions dated
+// within the range, transfers and split transactions' categories aside. A
+// dry run only reports the changes. Otherwise they are saved together and
+// each changed transaction is audited.
+func (s *RuleService) Apply(ctx context.Context, ownerID string, req domain.ApplyRulesRequest) (*domain.ApplyRulesResult, error) {
+	if req.DateFrom > req.DateTo {
+		return nil, ErrDateRange
+	}
+	rules, err := loadRules(ctx, s.repo, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ApplyRulesResult{DryRun: req.DryRun, Changes: []domain.RuleChange{}}
+	before := map[string]domain.Transaction{}
+	filter := domain.TransactionFilter{DateFrom: req.DateFrom, DateTo: req.DateTo}
+	err = s.transactionRepo.Export(ctx, ownerID, filter, func(t domain.Transaction) error {
+		if t.TransferID != nil {
+			return nil
+		}
+		result.Checked++
+		if change, ok := rules.change(t); ok {
+			result.Changes = append(result.Changes, change)
+			before[t.ID] = t
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Changed = len(result.Changes)
+
+	if req.DryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+	if err := s.transactionRepo.ApplyRuleChanges(ctx, ownerID, result.Changes); err != nil {
+		return nil, err
+	}
+	for _, change := range result.Changes {
+		after, err := s.transactionRepo.GetByID(ctx, ownerID, change.TransactionID)
+		if err != nil {
+			continue // deleted in the meantime
+		}
+		s.audit.Record(ctx, "update", auditTransaction, change.TransactionID, before[change.TransactionID], after)
+	}
+	return result, nil
+}
+
+// loadRules returns the owner's enabled rules ready for matching.
+func loadRules(ctx context.Context, repo *repository.RuleRepository, ownerID string) (ruleSet, error) {
+	rules, err := repo.GetEnabled(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return compileRules(rules), nil
+}
+
+// compileRules prepares rules, already in the order they run, for matching.
+func compileRules(rules []domain.Rule) ruleSet {
+	set := make(ruleSet, 0, len(rules))
+	for _, rule := range rules {
+		r := compiledRule{Rule: rule}
+		if c := rule.Conditions.DescriptionContains; c != nil {
+			r.contains = strings.ToLower(*c)
+		}
+		if c := rule.Conditions.DescriptionRegex; c != nil {
+			var err error
+			if r.regex, err = regexp.Compile(*c); err != nil {
+				continue // checked when saved, never expected
+			}
+		}
+		set = append(set, r)
+	}
+	return set
+}
+
+// ruleSet is an owner's enabled rules in the order they run.
+type ruleSet []compiledRule
+
+type compiledRule struct {
+	domain.Rule
+	contains string // lowercase DescriptionContains
+	regex    *regexp.Regexp
+}
+
+func (r compiledRule) matches(typ string, amount decimal.Decimal, description *string) bool {
+	c := r.Conditions
+	if c.Type != nil && *c.Type != typ {
+		return false
+	}
+	if c.AmountMin != nil && amount.LessThan(*c.AmountMin) {
+		return false
+	}
+	if c.AmountMax != nil && amount.GreaterThan(*c.AmountMax) {
+		return false
+	}
+	if c.DescriptionContains != nil && (description == nil || !strings.Contains(strings.ToLower(*description), r.contains)) {
+		return false
+	}
+	if r.regex != nil && (description == nil || !r.regex.MatchString(*description)) {
+		return false
+	}
+	return true
+}
+
+// ruleOutcome is what the matching rules set: the first rule to set the
+// category, payee or status wins and tags add up.
+type ruleOutcome struct {
+	rules      []string
+	categoryID *string
+	payeeID    *string
+	status     *string
+	tags       []string
+}
+
+func (rs ruleSet) evaluate(typ string, amount decimal.Decimal, description *string) ruleOutcome {
+	var out ruleOutcome
+	for _, r := range rs {
+		if !r.matches(typ, amount, description) {
+			continue
+		}
+		out.rules = append(out.rules, r.Name)
+		a := r.Actions
+		if out.categoryID == nil {
+			out.categoryID = a.CategoryID
+		}
+		if out.payeeID == nil {
+			out.payeeID = a.PayeeID
+		}
+		if out.status == nil {
+			out.status = a.Status
+		}
+		out.tags = append(out.tags, a.Tags...)
+	}
+	out.tags = domain.NormalizeTags(out.tags)
+	return out
+}
+
+// fill completes a new transaction with what the matching rules set. Values
+// already in the request are kept and tags are added to the given ones.
+func (rs ruleSet) fill(req *domain.CreateTransactionRequest) {
+	out := rs.evaluate(req.Type, req.Amount, req.Description)
+	if len(out.rules) == 0 {
+		return
+	}
+	if req.CategoryID == "" && len(req.Splits) == 0 && out.categoryID != nil {
+		req.CategoryID = *out.categoryID
+	}
+	if req.PayeeID == nil {
+		req.PayeeID = out.payeeID
+	}
+	if req.Status == "" && out.status != nil {
+		req.Status = *out.status
+	}
+	if len(out.tags) > 0 {
+		req.Tags = domain.NormalizeTags(append(req.Tags, out.tags...))
+	}
+}
+
+// change returns what the matching rules would change on an existing
+// transaction, if anything. Split transactions keep their categories.
+func (rs ruleSet) change(t domain.Transaction) (domain.RuleChange, bool) {
+	out := rs.evaluate(t.Type, t.Amount, t.Description)
+	change := domain.RuleChange{TransactionID: t.ID, Date: t.Date, Description: t.Description, Rules: out.rules}
+	if out.categoryID != nil && len(t.Splits) == 0 && (t.CategoryID == nil || *t.CategoryID != *out.categoryID) {
+		change.CategoryID = out.categoryID
+	}
+	if out.payeeID != nil && (t.PayeeID == nil || *t.PayeeID != *out.payeeID) {
+		change.PayeeID = out.payeeID
+	}
+	if out.status != nil && t.Status != *out.status {
+		change.Status = out.status
+	}
+	for _, tag := range out.tags {
+		if !slices.Contains(t.Tags, tag) {
+			change.AddTags = append(change.AddTags, tag)
+		}
+	}
+	changed := change.CategoryID != nil || change.PayeeID != nil || change.Status != nil || len(change.AddTags) > 0
+	return change, changed
+}
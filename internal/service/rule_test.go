@@ -0,0 +1,153 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"personal-finance-backend/internal/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestCompiledRuleMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  domain.RuleConditions
+		typ         string
+		amount      string
+		description *string
+		want        bool
+	}{
+		{"contains is case-insensitive", domain.RuleConditions{DescriptionContains: ptr("grab")}, "expense", "10", ptr("GRAB*RIDE"), true},
+		{"contains misses", domain.RuleConditions{DescriptionContains: ptr("gojek")}, "expense", "10", ptr("GRAB*RIDE"), false},
+		{"contains needs a description", domain.RuleConditions{DescriptionContains: ptr("grab")}, "expense", "10", nil, false},
+		{"regex", domain.RuleConditions{DescriptionRegex: ptr(`^GRAB\*`)}, "expense", "10", ptr("GRAB*FOOD"), true},
+		{"regex is case-sensitive by default", domain.RuleConditions{DescriptionRegex: ptr(`^grab`)}, "expense", "10", ptr("GRAB*FOOD"), false},
+		{"regex needs a description", domain.RuleConditions{DescriptionRegex: ptr(`.*`)}, "expense", "10", nil, false},
+		{"type", domain.RuleConditions{Type: ptr("income")}, "expense", "10", nil, false},
+		{"amount_min is inclusive", domain.RuleConditions{AmountMin: ptr(decimal.NewFromInt(10))}, "expense", "10", nil, true},
+		{"below amount_min", domain.RuleConditions{AmountMin: ptr(decimal.NewFromInt(10))}, "expense", "9.99", nil, false},
+		{"amount_max is inclusive", domain.RuleConditions{AmountMax: ptr(decimal.NewFromInt(10))}, "expense", "10", nil, true},
+		{"above amount_max", domain.RuleConditions{AmountMax: ptr(decimal.NewFromInt(10))}, "expense", "10.01", nil, false},
+		{
+			"every condition has to hold",
+			domain.RuleConditions{DescriptionContains: ptr("grab"), Type: ptr("expense"), AmountMax: ptr(decimal.NewFromInt(5))},
+			"expense", "10", ptr("GRAB*RIDE"), false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := compileRules([]domain.Rule{{Name: "r", Conditions: tt.conditions}})
+			if got := rs[0].matches(tt.typ, decimal.RequireFromString(tt.amount), tt.description); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleSetEvaluate(t *testing.T) {
+	rs := compileRules([]domain.Rule{
+		{Name: "grab food", Conditions: domain.RuleConditions{DescriptionContains: ptr("grab*food")},
+			Actions: domain.RuleActions{CategoryID: ptr("food"), Tags: []string{"Food"}}},
+		{Name: "grab", Conditions: domain.RuleConditions{DescriptionContains: ptr("grab")},
+			Actions: domain.RuleActions{CategoryID: ptr("transport"), PayeeID: ptr("grab"), Tags: []string{"grab", "food"}}},
+		{Name: "large", Conditions: domain.RuleConditions{AmountMin: ptr(decimal.NewFromInt(1000))},
+			Actions: domain.RuleActions{Status: ptr("pending")}},
+	})
+
+	tests := []struct {
+		name         string
+		description  string
+		amount       int64
+		wantRules    []string
+		wantCategory *string
+		wantPayee    *string
+		wantStatus   *string
+		wantTags     []string
+	}{
+		{"first rule wins, tags add up", "GRAB*FOOD Jakarta", 50, []string{"grab food", "grab"}, ptr("food"), ptr("grab"), nil, []string{"food", "grab"}},
+		{"later rule fills the rest", "GRAB*RIDE", 50, []string{"grab"}, ptr("transport"), ptr("grab"), nil, []string{"food", "grab"}},
+		{"all three", "GRAB*RIDE", 5000, []string{"grab", "large"}, ptr("transport"), ptr("grab"), ptr("pending"), []string{"food", "grab"}},
+		{"no match", "Salary", 50, nil, nil, nil, nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := rs.evaluate("expense", decimal.NewFromInt(tt.amount), &tt.description)
+			if !slices.Equal(out.rules, tt.wantRules) {
+				t.Errorf("rules = %v, want %v", out.rules, tt.wantRules)
+			}
+			if !equalPtr(out.categoryID, tt.wantCategory) {
+				t.Errorf("category = %v, want %v", deref(out.categoryID), deref(tt.wantCategory))
+			}
+			if !equalPtr(out.payeeID, tt.wantPayee) {
+				t.Errorf("payee = %v, want %v", deref(out.payeeID), deref(tt.wantPayee))
+			}
+			if !equalPtr(out.status, tt.wantStatus) {
+				t.Errorf("status = %v, want %v", deref(out.status), deref(tt.wantStatus))
+			}
+			if !slices.Equal(out.tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", out.tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestRuleSetFillKeepsRequestValues(t *testing.T) {
+	rs := compileRules([]domain.Rule{
+		{Name: "grab", Conditions: domain.RuleConditions{DescriptionContains: ptr("grab")},
+			Actions: domain.RuleActions{CategoryID: ptr("transport"), PayeeID: ptr("grab"), Status: ptr("pending"), Tags: []string{"grab"}}},
+	})
+
+	req := domain.CreateTransactionRequest{Type: "expense", Amount: decimal.NewFromInt(10), Description: ptr("GRAB*RIDE"),
+		CategoryID: "given", Status: "completed", Tags: []string{"work"}}
+	rs.fill(&req)
+	if req.CategoryID != "given" || req.Status != "completed" {
+		t.Errorf("fill() overwrote the request: category %s, status %s", req.CategoryID, req.Status)
+	}
+	if req.PayeeID == nil || *req.PayeeID != "grab" {
+		t.Errorf("payee = %v, want grab", deref(req.PayeeID))
+	}
+	if want := []string{"grab", "work"}; !slices.Equal(req.Tags, want) {
+		t.Errorf("tags = %v, want %v", req.Tags, want)
+	}
+
+	split := domain.CreateTransactionRequest{Type: "expense", Amount: decimal.NewFromInt(10), Description: ptr("GRAB*RIDE"),
+		Splits: []domain.SplitRequest{{CategoryID: "line", Amount: decimal.NewFromInt(10)}}}
+	rs.fill(&split)
+	if split.CategoryID != "" {
+		t.Errorf("fill() set category %s on a split transaction", split.CategoryID)
+	}
+}
+
+func TestRuleSetChange(t *testing.T) {
+	rs := compileRules([]domain.Rule{
+		{Name: "grab", Conditions: domain.RuleConditions{DescriptionContains: ptr("grab")},
+			Actions: domain.RuleActions{CategoryID: ptr("transport"), Tags: []string{"grab"}}},
+	})
+
+	unchanged := domain.Transaction{ID: "1", Type: "expense", Amount: decimal.NewFromInt(10), Description: ptr("GRAB"),
+		CategoryID: ptr("transport"), Tags: []string{"grab"}}
+	if _, changed := rs.change(unchanged); changed {
+		t.Error("change() reported a change for a transaction that already matches")
+	}
+
+	moved := unchanged
+	moved.CategoryID, moved.Tags = ptr("food"), nil
+	change, changed := rs.change(moved)
+	if !changed || change.CategoryID == nil || *change.CategoryID != "transport" || !slices.Equal(change.AddTags, []string{"grab"}) {
+		t.Errorf("change() = %+v, %v; want the category and tag changed", change, changed)
+	}
+
+	split := moved
+	split.Tags = []string{"grab"}
+	split.Splits = []domain.TransactionSplit{{CategoryID: "food"}}
+	if _, changed := rs.change(split); changed {
+		t.Error("change() recategorized a split transaction")
+	}
+}
+
+func equalPtr(a, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
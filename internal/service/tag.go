@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+)
+
+// ErrTagNameEmpty is returned when a tag name is blank once trimmed.
+var ErrTagNameEmpty = errors.New("tag name cannot be blank")
+
+type TagService struct {
+	repo *repository.TagRepository
+}
+
+func NewTagService(repo *repository.TagRepository) *TagService {
+	return &TagService{repo: repo}
+}
+
+func (s *TagService) Create(ctx context.Context, ownerID string, req domain.CreateTagRequest) (*domain.Tag, error) {
+	req.Name = domain.NormalizeTag(req.Name)
+	if req.Name == "" {
+		return nil, ErrTagNameEmpty
+	}
+	return s.repo.Create(ctx, ownerID, req)
+}
+
+func (s *TagService) GetAll(ctx context.Context, ownerID string) ([]domain.Tag, error) {
+	return s.repo.GetAll(ctx, ownerID)
+}
+
+func (s *TagService) GetByID(ctx context.Context, ownerID, id string) (*domain.Tag, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *TagService) Update(ctx context.Context, ownerID, id string, req domain.UpdateTagRequest) (*domain.Tag, error) {
+	req.Name = domain.NormalizeTag(req.Name)
+	if req.Name == "" {
+		return nil, ErrTagNameEmpty
+	}
+	return s.repo.Update(ctx, ownerID, id, req)
+}
+
+func (s *TagService) Delete(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
@@ -2,35 +2,308 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"personal-finance-backend/internal/domain"
 	"personal-finance-backend/internal/repository"
+
+	"github.com/shopspring/decimal"
 )
 
+// ErrCurrencyMismatch is returned when a transaction is booked against an
+// account that holds a different currency.
+var ErrCurrencyMismatch = errors.New("transaction currency does not match account currency")
+
+// ErrTransferLeg is returned when a single leg of a transfer is edited or
+// deleted through the transactions API instead of /transfers.
+var ErrTransferLeg = errors.New("transaction is part of a transfer, use /api/v1/transfers instead")
+
+// ErrSplitSum is returned when the lines of a split transaction don't add up
+// to its amount.
+var ErrSplitSum = errors.New("split amounts must add up to the transaction amount")
+
+// ErrCategoryType is returned when a transaction would be booked under a
+// category of the other type, e.g. an expense under an income category.
+var ErrCategoryType = errors.New("category type must match the transaction type")
+
+// ErrCategoryRequired is returned when a transaction is created without a
+// category and neither split lines, a rule nor the payee provide one.
+var ErrCategoryRequired = errors.New("category_id is required unless splits are given, a rule matches or the payee has a default category")
+
 type TransactionService struct {
-	repo *repository.TransactionRepository
+	repo         *repository.TransactionRepository
+	categoryRepo *repository.CategoryRepository
+	accountRepo  *repository.AccountRepository
+	payeeRepo    *repository.PayeeRepository
+	ruleRepo     *repository.RuleRepository
+	audit        *AuditService
+	baseCurrency string
 }
 
-func NewTransactionService(repo *repository.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+func NewTransactionService(repo *repository.TransactionRepository, categoryRepo *repository.CategoryRepository,
+	accountRepo *repository.AccountRepository, payeeRepo *repository.PayeeRepository, ruleRepo *repository.RuleRepository,
+	audit *AuditService, baseCurrency string) *TransactionService {
+	return &TransactionService{repo: repo, categoryRepo: categoryRepo, accountRepo: accountRepo, payeeRepo: payeeRepo,
+		ruleRepo: ruleRepo, audit: audit, baseCurrency: baseCurrency}
 }
 
-func (s *TransactionService) Create(ctx context.Context, req domain.CreateTransactionRequest) (*domain.Transaction, error) {
-	return s.repo.Create(ctx, req)
+// Create records a transaction. Without a category_id it takes the first
+// split's category; failing that the owner's rules fill in the category,
+// payee, tags and status, and then the payee's default category is used.
+func (s *TransactionService) Create(ctx context.Context, ownerID string, req domain.CreateTransactionRequest) (*domain.Transaction, error) {
+	if req.CategoryID == "" && len(req.Splits) == 0 {
+		rules, err := loadRules(ctx, s.ruleRepo, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		rules.fill(&req)
+	}
+
+	var payee *domain.Payee
+	if req.PayeeID != nil {
+		var err error
+		if payee, err = lookupPayee(ctx, s.payeeRepo, ownerID, *req.PayeeID); err != nil {
+			return nil, err
+		}
+	}
+	if req.CategoryID == "" && len(req.Splits) > 0 {
+		req.CategoryID = req.Splits[0].CategoryID
+	}
+	if req.CategoryID == "" && payee != nil && payee.DefaultCategoryID != nil {
+		// A default category that has since been deleted, or that has the
+		// other type, doesn't apply to this transaction.
+		category, err := lookupCategory(ctx, s.categoryRepo, ownerID, *payee.DefaultCategoryID)
+		if err != nil && !errors.Is(err, ErrUnknownCategory) {
+			return nil, err
+		}
+		if err == nil && category.Type == req.Type {
+			req.CategoryID = category.ID
+		}
+	}
+	if req.CategoryID == "" {
+		return nil, ErrCategoryRequired
+	}
+	category, err := lookupCategory(ctx, s.categoryRepo, ownerID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.Type != req.Type {
+		return nil, ErrCategoryType
+	}
+	if req.AccountID != nil {
+		account, err := lookupAccount(ctx, s.accountRepo, ownerID, *req.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		// Default to the account's currency when none is given
+		if req.Currency == "" {
+			req.Currency = account.Currency
+		}
+		if req.Currency != account.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+	}
+	if req.Currency == "" {
+		req.Currency = "IDR"
+	}
+	if err := domain.ValidateAmount(req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+	if err := s.checkSplits(ctx, ownerID, req.Splits, req.Type, req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+	tx, err := s.repo.Create(ctx, ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "create", auditTransaction, tx.ID, nil, tx)
+	return tx, nil
+}
+
+func (s *TransactionService) GetAll(ctx context.Context, ownerID string, filter domain.TransactionFilter) ([]domain.Transaction, int, error) {
+	transactions, total, err := s.repo.GetAll(ctx, ownerID, withConvertTo(filter, s.baseCurrency))
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range transactions {
+		roundConverted(&transactions[i])
+	}
+	return transactions, total, nil
 }
 
-func (s *TransactionService) GetAll(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, int, error) {
-	return s.repo.GetAll(ctx, filter)
+func (s *TransactionService) Export(ctx context.Context, ownerID string, filter domain.TransactionFilter, fn func(domain.Transaction) error) error {
+	return s.repo.Export(ctx, ownerID, withConvertTo(filter, s.baseCurrency), func(t domain.Transaction) error {
+		roundConverted(&t)
+		return fn(t)
+	})
+}
+
+// roundConverted rounds a converted amount to its currency's precision.
+func roundConverted(t *domain.Transaction) {
+	if t.ConvertedAmount != nil {
+		rounded := domain.RoundAmount(*t.ConvertedAmount, t.ConvertedCurrency)
+		t.ConvertedAmount = &rounded
+	}
+}
+
+func (s *TransactionService) GetByID(ctx context.Context, ownerID, id string) (*domain.Transaction, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *TransactionService) Update(ctx context.Context, ownerID, id string, req domain.UpdateTransactionRequest) (*domain.Transaction, error) {
+	existing, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.TransferID != nil {
+		return nil, ErrTransferLeg
+	}
+	if err := s.checkCategoryType(ctx, ownerID, existing, req); err != nil {
+		return nil, err
+	}
+	if req.PayeeID != nil && *req.PayeeID != "" {
+		if _, err := lookupPayee(ctx, s.payeeRepo, ownerID, *req.PayeeID); err != nil {
+			return nil, err
+		}
+	}
+
+	accountID, txType, currency, amount := existing.AccountID, existing.Type, existing.Currency, existing.Amount
+	if req.Type != nil {
+		txType = *req.Type
+	}
+	if req.AccountID != nil {
+		accountID = req.AccountID
+	}
+	if req.Currency != nil {
+		currency = *req.Currency
+	}
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if err := domain.ValidateAmount(amount, currency); err != nil {
+		return nil, err
+	}
+
+	// The lines have to match the type and amount whenever either changes
+	if req.Splits != nil {
+		if err := s.checkSplits(ctx, ownerID, *req.Splits, txType, amount, currency); err != nil {
+			return nil, err
+		}
+	} else if len(existing.Splits) > 0 && (req.Type != nil || req.Amount != nil || req.Currency != nil) {
+		if err := s.checkSplits(ctx, ownerID, splitRequests(existing.Splits), txType, amount, currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.AccountID != nil || req.Currency != nil {
+		if accountID != nil {
+			account, err := lookupAccount(ctx, s.accountRepo, ownerID, *accountID)
+			if err != nil {
+				return nil, err
+			}
+			if currency != account.Currency {
+				return nil, ErrCurrencyMismatch
+			}
+		}
+	}
+	tx, err := s.repo.Update(ctx, ownerID, id, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "update", auditTransaction, id, existing, tx)
+	return tx, nil
+}
+
+func (s *TransactionService) Delete(ctx context.Context, ownerID, id string) error {
+	existing, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if existing.TransferID != nil {
+		return ErrTransferLeg
+	}
+	if err := s.repo.Delete(ctx, ownerID, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, "delete", auditTransaction, id, existing, nil)
+	return nil
+}
+
+// checkCategoryType checks that the category a transaction ends up with has
+// the type it ends up with, whenever an update changes either. A category
+// that has since been deleted is only an error when the update sets it.
+func (s *TransactionService) checkCategoryType(ctx context.Context, ownerID string, existing *domain.Transaction, req domain.UpdateTransactionRequest) error {
+	if req.CategoryID == nil && req.Type == nil {
+		return nil
+	}
+	categoryID, txType := existing.CategoryID, existing.Type
+	if req.CategoryID != nil {
+		categoryID = req.CategoryID
+	}
+	if req.Type != nil {
+		txType = *req.Type
+	}
+	if categoryID == nil {
+		return nil
+	}
+
+	category, err := lookupCategory(ctx, s.categoryRepo, ownerID, *categoryID)
+	if errors.Is(err, ErrUnknownCategory) && req.CategoryID == nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if category.Type != txType {
+		return ErrCategoryType
+	}
+	return nil
 }
 
-func (s *TransactionService) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
-	return s.repo.GetByID(ctx, id)
+// checkSplits validates the lines of a split transaction: every category is
+// usable by the owner and has the transaction's type, every amount fits the
+// currency and together they add up to the transaction's amount. No lines
+// means the transaction isn't split.
+func (s *TransactionService) checkSplits(ctx context.Context, ownerID string, splits []domain.SplitRequest, txType string,
+	amount decimal.Decimal, currency string) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	sum := decimal.Zero
+	for _, split := range splits {
+		category, err := lookupCategory(ctx, s.categoryRepo, ownerID, split.CategoryID)
+		if err != nil {
+			return err
+		}
+		if category.Type != txType {
+			return ErrCategoryType
+		}
+		if err := domain.ValidateAmount(split.Amount, currency); err != nil {
+			return err
+		}
+		sum = sum.Add(split.Amount)
+	}
+	if !sum.Equal(amount) {
+		return ErrSplitSum
+	}
+	return nil
 }
 
-func (s *TransactionService) Update(ctx context.Context, id string, req domain.UpdateTransactionRequest) (*domain.Transaction, error) {
-	return s.repo.Update(ctx, id, req)
+// splitRequests turns stored split lines back into the lines of a request.
+func splitRequests(splits []domain.TransactionSplit) []domain.SplitRequest {
+	reqs := make([]domain.SplitRequest, len(splits))
+	for i, split := range splits {
+		reqs[i] = domain.SplitRequest{CategoryID: split.CategoryID, Amount: split.Amount, Note: split.Note}
+	}
+	return reqs
 }
 
-func (s *TransactionService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+// Restore undoes the soft delete of a transaction.
+func (s *TransactionService) Restore(ctx context.Context, ownerID, id string) (*domain.Transaction, error) {
+	tx, err := s.repo.Restore(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "restore", auditTransaction, id, nil, tx)
+	return tx, nil
 }
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"personal-finance-backend/internal/domain"
+	"personal-finance-backend/internal/repository"
+)
+
+// ErrSameAccount is returned when a transfer's source and destination are the same account.
+var ErrSameAccount = errors.New("source and destination accounts must differ")
+
+// ErrTransferAccountMissing is returned when moving one side of a transfer
+// whose other leg has no account on record.
+var ErrTransferAccountMissing = errors.New("transfer leg has no account, set both from_account_id and to_account_id")
+
+type TransferService struct {
+	repo        *repository.TransferRepository
+	accountRepo *repository.AccountRepository
+	audit       *AuditService
+}
+
+func NewTransferService(repo *repository.TransferRepository, accountRepo *repository.AccountRepository, audit *AuditService) *TransferService {
+	return &TransferService{repo: repo, accountRepo: accountRepo, audit: audit}
+}
+
+func (s *TransferService) Create(ctx context.Context, ownerID string, req domain.CreateTransferRequest) (*domain.Transfer, error) {
+	currency, err := s.accountsCurrency(ctx, ownerID, req.FromAccountID, req.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateAmount(req.Amount, currency); err != nil {
+		return nil, err
+	}
+	transfer, err := s.repo.Create(ctx, ownerID, req, currency)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "create", auditTransfer, transfer.ID, nil, transfer)
+	return transfer, nil
+}
+
+func (s *TransferService) GetAll(ctx context.Context, ownerID string, filter domain.TransferFilter) ([]domain.Transfer, int, error) {
+	return s.repo.GetAll(ctx, ownerID, filter)
+}
+
+func (s *TransferService) GetByID(ctx context.Context, ownerID, id string) (*domain.Transfer, error) {
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *TransferService) Update(ctx context.Context, ownerID, id string, req domain.UpdateTransferRequest) (*domain.Transfer, error) {
+	existing, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount != nil {
+		if err := domain.ValidateAmount(*req.Amount, existing.From.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.FromAccountID != nil || req.ToAccountID != nil {
+		var fromID, toID string
+		if existing.From.AccountID != nil {
+			fromID = *existing.From.AccountID
+		}
+		if existing.To.AccountID != nil {
+			toID = *existing.To.AccountID
+		}
+		if req.FromAccountID != nil {
+			fromID = *req.FromAccountID
+		}
+		if req.ToAccountID != nil {
+			toID = *req.ToAccountID
+		}
+		if fromID == "" || toID == "" {
+			return nil, ErrTransferAccountMissing
+		}
+
+		currency, err := s.accountsCurrency(ctx, ownerID, fromID, toID)
+		if err != nil {
+			return nil, err
+		}
+		if currency != existing.From.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+	}
+	transfer, err := s.repo.Update(ctx, ownerID, id, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "update", auditTransfer, id, existing, transfer)
+	return transfer, nil
+}
+
+func (s *TransferService) Delete(ctx context.Context, ownerID, id string) error {
+	existing, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, ownerID, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, "delete", auditTransfer, id, existing, nil)
+	return nil
+}
+
+// Restore undoes the soft delete of a transfer.
+func (s *TransferService) Restore(ctx context.Context, ownerID, id string) (*domain.Transfer, error) {
+	transfer, err := s.repo.Restore(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, "restore", auditTransfer, id, nil, transfer)
+	return transfer, nil
+}
+
+// accountsCurrency checks that both accounts belong to the owner, differ and
+// share a currency, and returns that currency.
+func (s *TransferService) accountsCurrency(ctx context.Context, ownerID, fromID, toID string) (string, error) {
+	if fromID == toID {
+		return "", ErrSameAccount
+	}
+	from, err := lookupAccount(ctx, s.accountRepo, ownerID, fromID)
+	if err != nil {
+		return "", err
+	}
+	to, err := lookupAccount(ctx, s.accountRepo, ownerID, toID)
+	if err != nil {
+		return "", err
+	}
+	if from.Currency != to.Currency {
+		return "", ErrCurrencyMismatch
+	}
+	return from.Currency, nil
+}
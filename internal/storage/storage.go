@@ -0,0 +1,46 @@
+// Package storage keeps uploaded files, such as transaction attachments,
+// outside the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"personal-finance-backend/internal/config"
+)
+
+// ErrNotFound is returned when opening a key that holds no file.
+var ErrNotFound = errors.New("file not found")
+
+// Storage stores files under keys chosen by the caller. Keys are
+// slash-separated paths such as "owner/transaction/file".
+type Storage interface {
+	// Put stores size bytes read from r under key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the file stored under key, or ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file stored under key. Missing files are not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the storage selected by STORAGE_DRIVER.
+func New(ctx context.Context, cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "local":
+		return NewLocalStorage(cfg.StorageDir)
+	case "s3":
+		return NewS3Storage(ctx, S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, use local or s3", cfg.StorageDriver)
+	}
+}
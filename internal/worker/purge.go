@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"personal-finance-backend/internal/service"
+)
+
+// PurgeWorker periodically removes soft-deleted rows past their retention.
+type PurgeWorker struct {
+	service  *service.PurgeService
+	interval time.Duration
+}
+
+func NewPurgeWorker(s *service.PurgeService, interval time.Duration) *PurgeWorker {
+	return &PurgeWorker{service: s, interval: interval}
+}
+
+// Run purges immediately and then on every tick until ctx is cancelled.
+func (w *PurgeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		transactions, categories, err := w.service.PurgeDeleted(ctx, time.Now())
+		if err != nil {
+			log.Println("Purge worker:", err)
+		} else if transactions > 0 || categories > 0 {
+			log.Printf("Purge worker: removed %d transaction(s) and %d category(ies)", transactions, categories)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
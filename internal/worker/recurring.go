@@ -0,0 +1,42 @@
+// Package worker contains background jobs that run alongside the API server.
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"personal-finance-backend/internal/service"
+)
+
+// RecurringWorker periodically turns due recurring rules into transactions.
+type RecurringWorker struct {
+	service  *service.RecurringService
+	interval time.Duration
+}
+
+func NewRecurringWorker(s *service.RecurringService, interval time.Duration) *RecurringWorker {
+	return &RecurringWorker{service: s, interval: interval}
+}
+
+// Run materializes due rules immediately and then on every tick until ctx
+// is cancelled.
+func (w *RecurringWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := w.service.MaterializeDue(ctx, time.Now())
+		if err != nil {
+			log.Println("Recurring worker:", err)
+		} else if created > 0 {
+			log.Printf("Recurring worker: created %d transaction(s)", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
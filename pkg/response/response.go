@@ -23,3 +23,13 @@ func Error(c *gin.Context, status int, message string) {
 		Message: message,
 	})
 }
+
+// ErrorWithData is like Error but also returns details the client can act on,
+// such as per-row validation errors.
+func ErrorWithData(c *gin.Context, status int, message string, data interface{}) {
+	c.JSON(status, Response{
+		Status:  status,
+		Message: message,
+		Data:    data,
+	})
+}